@@ -0,0 +1,157 @@
+// Package explain turns findings into plain-language guidance for non-specialists
+package explain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// Guide is the plain-language explanation of a finding
+type Guide struct {
+	Title       string
+	Explanation string
+	Impact      string
+	Remediation string
+	References  []string
+}
+
+// guide is a built-in explanation for template IDs matching Patterns (path.Match globs)
+type guide struct {
+	Patterns []string
+	Guide
+}
+
+// guides is checked in order, so specific patterns come before broad ones
+var guides = []guide{
+	{[]string{"*csp*", "missing-header-content-security-policy", "zap-10038", "zap-10055"}, Guide{
+		Title:       "Content Security Policy (CSP) missing or weak",
+		Explanation: "A CSP is a list, sent by your site, of where scripts, styles and images may be loaded from. Yours is missing or too permissive.",
+		Impact:      "If an attacker manages to inject script into a page, a good CSP stops it from running; without one, visitors' sessions and data can be stolen.",
+		Remediation: "Start with a report-only Content-Security-Policy, review what it would block, then enforce it. Avoid 'unsafe-inline' and wildcard sources.",
+	}},
+	{[]string{"zap-10020", "*x-frame-options*", "*clickjacking*"}, Guide{
+		Title:       "Page can be embedded by other sites (clickjacking)",
+		Explanation: "Other websites can show your pages inside an invisible frame.",
+		Impact:      "Visitors can be tricked into clicking buttons on your site (e.g. \"transfer\" or \"delete\") while thinking they are on another page.",
+		Remediation: "Send X-Frame-Options: DENY (or SAMEORIGIN) or a CSP frame-ancestors directive.",
+	}},
+	{[]string{"http-missing-security-headers*", "zap-10021", "zap-10035", "zap-10063", "missing-header-*"}, Guide{
+		Title:       "Missing security headers",
+		Explanation: "Your web server does not send some HTTP headers that tell browsers to switch on built-in protections.",
+		Impact:      "On their own these rarely get you hacked, but they make other attacks such as clickjacking or cross-site scripting easier to pull off.",
+		Remediation: "Add the missing headers (for example Strict-Transport-Security, X-Content-Type-Options, Referrer-Policy) in your web server, CDN or framework configuration.",
+	}},
+	{[]string{"zap-10010", "zap-10011", "zap-10054", "*cookie*"}, Guide{
+		Title:       "Cookies without security flags",
+		Explanation: "Some cookies are missing the Secure, HttpOnly or SameSite flags.",
+		Impact:      "Session cookies may leak over unencrypted connections or be read by injected scripts, letting someone log in as your user.",
+		Remediation: "Set Secure, HttpOnly and SameSite=Lax (or Strict) on session and authentication cookies.",
+	}},
+	{[]string{"cert-*", "expired-ssl*", "self-signed-ssl*", "mismatched-ssl*", "untrusted-root*"}, Guide{
+		Title:       "Certificate problem",
+		Explanation: "The HTTPS certificate is expired, self-signed or does not match the site name.",
+		Impact:      "Browsers show warnings, customers lose trust, and users learn to click through warnings that might one day be a real attack.",
+		Remediation: "Install a valid certificate from a trusted authority (e.g. Let's Encrypt) and automate its renewal.",
+	}},
+	{[]string{"ssl-*", "tls-*", "weak-cipher*", "deprecated-tls*"}, Guide{
+		Title:       "Weak HTTPS (TLS) configuration",
+		Explanation: "Your server still accepts old encryption protocols or ciphers.",
+		Impact:      "Attackers on the same network may be able to decrypt or tamper with traffic.",
+		Remediation: "Allow only TLS 1.2 and 1.3 with modern ciphers; Mozilla's SSL Configuration Generator gives ready-made settings.",
+	}},
+	{[]string{"*-panel", "*-login", "exposed-panels*"}, Guide{
+		Title:       "Admin or login page exposed to the internet",
+		Explanation: "A management or login interface is reachable by anyone.",
+		Impact:      "Attackers can try passwords or exploit known bugs in the panel to take over the system.",
+		Remediation: "Restrict access by VPN or IP allowlist, enforce strong passwords and multi-factor authentication, and keep the software updated.",
+	}},
+	{[]string{"*git-config*", "*-env*", "exposed-*", "*backup*", "*-disclosure"}, Guide{
+		Title:       "Sensitive file exposed",
+		Explanation: "A file that should stay private (configuration, backup, source control data) can be downloaded.",
+		Impact:      "Such files often contain passwords, API keys or source code that make a full compromise easy.",
+		Remediation: "Remove the file from the web root or block it in the server configuration, then rotate any secrets it contained.",
+	}},
+	{[]string{"tech-detect", "*-detect", "*-version"}, Guide{
+		Title:       "Technology fingerprint",
+		Explanation: "The scan identified software or versions your site runs. This is informational.",
+		Impact:      "Attackers use version information to look up known vulnerabilities.",
+		Remediation: "Keep the detected software updated and, where easy, hide version numbers in banners and headers.",
+	}},
+	{[]string{"open-port-*"}, Guide{
+		Title:       "Open network port",
+		Explanation: "A network service is listening and reachable on this port.",
+		Impact:      "Every reachable service is something attackers can probe; unneeded ones are unnecessary risk.",
+		Remediation: "Close or firewall ports that don't need to be public, and keep the remaining services patched.",
+	}},
+	{[]string{"CVE-*", "cve-*"}, Guide{
+		Title:       "Known vulnerability (CVE)",
+		Explanation: "The software matches a publicly known vulnerability with a CVE identifier.",
+		Impact:      "Public vulnerabilities are actively scanned for by attackers; depending on the CVE this can mean data theft or full takeover.",
+		Remediation: "Update the affected software to a fixed version. If you can't update right away, apply the vendor's workaround or restrict access.",
+	}},
+}
+
+// severityImpact is the fallback impact wording when no built-in guide matches
+var severityImpact = map[string]string{
+	"critical": "An attacker could likely take over the system or steal data. Fix immediately.",
+	"high":     "Serious weakness that attackers can realistically exploit. Fix within days.",
+	"medium":   "A weakness that is harder to exploit or limited in effect. Plan a fix within weeks.",
+	"low":      "Minor issue or hardening opportunity. Fix when convenient.",
+	"info":     "Informational; no direct risk, but useful context about your setup.",
+}
+
+// Explain builds guidance for f from the built-in mapping and the finding itself
+func Explain(f schema.Finding) Guide {
+	g := lookup(f.Template)
+	if g.Title == "" {
+		g = lookup(f.ID)
+	}
+	if g.Title == "" {
+		g.Title = f.Template
+		g.Explanation = f.Description
+		g.Impact = severityImpact[strings.ToLower(f.Severity)]
+	}
+	if f.Recommendation != "" {
+		g.Remediation = f.Recommendation
+	}
+	g.References = references(f)
+	return g
+}
+
+func lookup(id string) Guide {
+	if id == "" {
+		return Guide{}
+	}
+	for _, gd := range guides {
+		for _, p := range gd.Patterns {
+			if ok, _ := path.Match(strings.ToLower(p), strings.ToLower(id)); ok {
+				return gd.Guide
+			}
+		}
+	}
+	return Guide{}
+}
+
+// references collects links from tags, and NVD pages for CVE IDs
+func references(f schema.Finding) []string {
+	seen := map[string]bool{}
+	var refs []string
+	add := func(r string) {
+		if !seen[r] {
+			seen[r] = true
+			refs = append(refs, r)
+		}
+	}
+	for _, v := range append([]string{f.ID, f.Template}, f.Tags...) {
+		switch {
+		case strings.HasPrefix(v, "http://"), strings.HasPrefix(v, "https://"):
+			add(v)
+		case strings.HasPrefix(strings.ToUpper(v), "CVE-"):
+			add(fmt.Sprintf("https://nvd.nist.gov/vuln/detail/%s", strings.ToUpper(v)))
+		}
+	}
+	return refs
+}
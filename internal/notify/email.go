@@ -0,0 +1,258 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// SMTPConfig says how report emails are sent
+type SMTPConfig struct {
+	// Host and Port of the mail server; port 465 uses implicit TLS, others
+	// STARTTLS when the server offers it (default 587)
+	Host string
+	Port int
+	// User and Password log in with PLAIN auth, which net/smtp only allows over
+	// TLS or to localhost; no auth when User is empty
+	User     string
+	Password string
+	// From is the sender address (default User)
+	From string
+}
+
+func (c SMTPConfig) sender() string {
+	if c.From != "" {
+		return c.From
+	}
+	return c.User
+}
+
+// ValidateSMTP checks cfg and the recipients before anything is rendered
+func ValidateSMTP(cfg SMTPConfig, to []string) error {
+	if cfg.Host == "" {
+		return errors.New("--email-to needs --smtp-host")
+	}
+	if cfg.Port < 0 || cfg.Port > 65535 {
+		return fmt.Errorf("invalid --smtp-port %d", cfg.Port)
+	}
+	if cfg.sender() == "" {
+		return errors.New("--email-to needs --email-from (or --smtp-user as the sender)")
+	}
+	if _, err := mail.ParseAddress(cfg.sender()); err != nil {
+		return fmt.Errorf("invalid --email-from %q: %w", cfg.sender(), err)
+	}
+	for _, addr := range to {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("invalid --email-to %q: %w", addr, err)
+		}
+	}
+	if cfg.User != "" && cfg.Password == "" {
+		return errors.New("--smtp-user needs a password: set YORO_SMTP_PASSWORD")
+	}
+	return nil
+}
+
+// SendReport emails a summary of res (score, grade, counts) to the recipients
+// with the attachments, typically the report's PDF and HTML
+func SendReport(cfg SMTPConfig, to []string, res schema.ScanResult, attachments []string) error {
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	from, err := mail.ParseAddress(cfg.sender())
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", cfg.sender(), err)
+	}
+	var rcpts []*mail.Address
+	var envelope []string
+	for _, addr := range to {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", addr, err)
+		}
+		rcpts = append(rcpts, a)
+		envelope = append(envelope, a.Address)
+	}
+	msg, err := reportEmail(from, rcpts, res, attachments)
+	if err != nil {
+		return err
+	}
+	if err := sendMail(cfg, from.Address, envelope, msg); err != nil {
+		return fmt.Errorf("failed to email the report via %s: %w", cfg.Host, err)
+	}
+	return nil
+}
+
+func sendMail(cfg SMTPConfig, from string, to []string, msg []byte) error {
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if cfg.Port == 465 {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: cfg.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	// Bound the whole conversation, not just the dial
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Minute))
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if _, isTLS := conn.(*tls.Conn); !isTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(&tls.Config{ServerName: cfg.Host}); err != nil {
+				return err
+			}
+		}
+	}
+	if cfg.User != "" {
+		if err := c.Auth(smtp.PlainAuth("", cfg.User, cfg.Password, cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// reportEmail builds the multipart message: a plain-text summary, then one
+// base64 part per attachment
+func reportEmail(from *mail.Address, to []*mail.Address, res schema.ScanResult, attachments []string) ([]byte, error) {
+	s := report.Summarize(res)
+	var buf bytes.Buffer
+	// The writer only writes once a part is created, so the headers go first
+	mw := multipart.NewWriter(&buf)
+
+	recipients := make([]string, len(to))
+	for i, a := range to {
+		recipients[i] = a.String()
+	}
+	subject := fmt.Sprintf("[yoro] Security report for %s: grade %s (score %d)", res.Target, s.Grade, s.Score)
+	header := []string{
+		"From: " + from.String(),
+		"To: " + strings.Join(recipients, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=" + mw.Boundary(),
+	}
+	buf.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	text, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(text)
+	if _, err := qp.Write([]byte(emailSummary(res, s, attachments))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, path := range attachments {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attachment: %w", err)
+		}
+		name := filepath.Base(path)
+		ctype := mime.TypeByExtension(filepath.Ext(name))
+		if ctype == "" {
+			ctype = "application/octet-stream"
+		}
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {ctype},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": name})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64Lines(part, data); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// emailSummary is the body: the headline numbers and what is attached
+func emailSummary(res schema.ScanResult, s report.Summary, attachments []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Security scan of %s\n", res.Target)
+	fmt.Fprintf(&b, "Scanned: %s\n\n", res.Timestamp.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Score:      %d (grade %s)\n", s.Score, s.Grade)
+	fmt.Fprintf(&b, "Risk score: %d / %d\n", s.RiskScore, report.MaxRiskScore)
+	fmt.Fprintf(&b, "Findings:   %d\n", s.Total)
+	for _, sev := range []string{"critical", "high", "medium", "low", "info"} {
+		fmt.Fprintf(&b, "  %-9s %d\n", sev+":", s.Counts[sev])
+	}
+	if failed := res.FailedScanners(); len(failed) > 0 {
+		fmt.Fprintf(&b, "\nIncomplete: %s did not finish, so their findings are missing.\n", strings.Join(failed, ", "))
+	}
+	if len(attachments) > 0 {
+		b.WriteString("\nThe full report is attached.\n")
+	}
+	b.WriteString("\n-- \nSent by yorosec-agent\n")
+	return b.String()
+}
+
+// writeBase64Lines encodes data in the 76-column lines mail requires
+func writeBase64Lines(w io.Writer, data []byte) error {
+	enc := base64.StdEncoding.EncodeToString(data)
+	for len(enc) > 76 {
+		if _, err := w.Write([]byte(enc[:76] + "\r\n")); err != nil {
+			return err
+		}
+		enc = enc[76:]
+	}
+	_, err := w.Write([]byte(enc + "\r\n"))
+	return err
+}
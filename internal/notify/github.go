@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// GitHubLabel marks the issues yoro files; only open issues with it are checked
+// for duplicates
+const GitHubLabel = "yoro"
+
+// githubMarker hides the template ID in the issue body for de-duplication
+var githubMarker = regexp.MustCompile(`<!-- yoro-template: (\S+) -->`)
+
+var githubRepo = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// ValidateGitHubRepo checks an owner/name repository reference
+func ValidateGitHubRepo(repo string) error {
+	if !githubRepo.MatchString(repo) {
+		return fmt.Errorf("invalid GitHub repository %q (expected owner/name)", repo)
+	}
+	return nil
+}
+
+// githubAPI is the REST endpoint; GITHUB_API_URL points it at GitHub Enterprise
+func githubAPI() string {
+	if u := os.Getenv("GITHUB_API_URL"); u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	return "https://api.github.com"
+}
+
+// CreateIssues opens one issue in repo for each new critical/high finding template
+// that has no open yoro issue yet. A failure to file one issue does not stop the
+// others; all failures are returned together.
+func CreateIssues(ctx context.Context, repo, token string, findings []schema.Finding) error {
+	gh := githubClient{repo: repo, token: token, http: &http.Client{Timeout: 30 * time.Second}}
+	serious := Serious(findings)
+	if len(serious) == 0 {
+		return nil
+	}
+	open, err := gh.openTemplates(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, f := range serious {
+		if open[templateOf(f)] {
+			continue
+		}
+		if err := gh.create(ctx, f); err != nil {
+			errs = append(errs, fmt.Errorf("failed to file GitHub issue for %s: %w", templateOf(f), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type githubClient struct {
+	repo  string
+	token string
+	http  *http.Client
+}
+
+// openTemplates returns the template IDs of the open issues carrying GitHubLabel
+func (c githubClient) openTemplates(ctx context.Context) (map[string]bool, error) {
+	templates := map[string]bool{}
+	for page := 1; ; page++ {
+		q := url.Values{"state": {"open"}, "labels": {GitHubLabel}, "per_page": {"100"}, "page": {fmt.Sprint(page)}}
+		var issues []struct {
+			Body string `json:"body"`
+		}
+		if err := c.do(ctx, http.MethodGet, "/repos/"+c.repo+"/issues?"+q.Encode(), nil, &issues); err != nil {
+			return nil, fmt.Errorf("failed to list open GitHub issues: %w", err)
+		}
+		for _, is := range issues {
+			if m := githubMarker.FindStringSubmatch(is.Body); m != nil {
+				templates[m[1]] = true
+			}
+		}
+		if len(issues) < 100 {
+			return templates, nil
+		}
+	}
+}
+
+func (c githubClient) create(ctx context.Context, f schema.Finding) error {
+	issue := map[string]any{
+		"title":  issueTitle(f),
+		"body":   issueBody(f) + fmt.Sprintf("\n<!-- yoro-template: %s -->\n", templateOf(f)),
+		"labels": []string{GitHubLabel, "security", "severity:" + strings.ToLower(f.Severity)},
+	}
+	return c.do(ctx, http.MethodPost, "/repos/"+c.repo+"/issues", issue, nil)
+}
+
+// do sends a REST call and decodes the JSON response into out when non-nil
+func (c githubClient) do(ctx context.Context, method, path string, in, out any) error {
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("Authorization", "Bearer "+c.token)
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if err := sendJSON(ctx, c.http, method, githubAPI()+path, header, in, out); err != nil {
+		return fmt.Errorf("GitHub API %s %s: %w", method, strings.SplitN(path, "?", 2)[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/explain"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// JiraConfig says where and how Jira issues are filed
+type JiraConfig struct {
+	// URL is the Jira base URL, e.g. https://acme.atlassian.net
+	URL string
+	// Project is the project key, e.g. SEC
+	Project string
+	// User is the account email for Jira Cloud (basic auth with Token); when empty
+	// Token is sent as a bearer personal access token (Jira Server/Data Center)
+	User  string
+	Token string
+	// IssueType is the type of filed issues (default Bug)
+	IssueType string
+	// MinSeverity is the least severe finding filed (default high)
+	MinSeverity string
+	// Attachments maps finding IDs to the report PDF of their scan, attached to the
+	// issue filed for the finding
+	Attachments map[string]string
+}
+
+// jiraPriority maps finding severities to Jira's default priority scheme
+var jiraPriority = map[string]string{
+	"critical": "Highest",
+	"high":     "High",
+	"medium":   "Medium",
+	"low":      "Low",
+	"info":     "Lowest",
+}
+
+var jiraProjectKey = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// jiraLabelUnsafe matches characters Jira labels cannot hold
+var jiraLabelUnsafe = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// JiraTemplateLabel is the label an issue carries for its finding template; open
+// issues with it are not filed again
+func JiraTemplateLabel(template string) string {
+	return "yoro-" + jiraLabelUnsafe.ReplaceAllString(template, "-")
+}
+
+// ValidateJira checks cfg before a scan starts
+func ValidateJira(cfg JiraConfig) error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid --jira-url %q (expected e.g. https://acme.atlassian.net)", cfg.URL)
+	}
+	if cfg.Project == "" {
+		return errors.New("--jira-url needs --jira-project")
+	}
+	if !jiraProjectKey.MatchString(cfg.Project) {
+		return fmt.Errorf("invalid --jira-project %q (expected a project key such as SEC)", cfg.Project)
+	}
+	if cfg.Token == "" {
+		return errors.New("--jira-url needs a token: set YORO_JIRA_TOKEN")
+	}
+	if cfg.MinSeverity != "" && schema.SeverityRank(cfg.MinSeverity) < 0 {
+		return fmt.Errorf("invalid --jira-min-severity %q (expected critical, high, medium, low or info)", cfg.MinSeverity)
+	}
+	return nil
+}
+
+// CreateJiraIssues files one Jira issue per new finding template at or above
+// cfg.MinSeverity, skipping templates that already have an unresolved issue in the
+// project. A failure to file one issue does not stop the others.
+func CreateJiraIssues(ctx context.Context, cfg JiraConfig, findings []schema.Finding) error {
+	if cfg.MinSeverity == "" {
+		cfg.MinSeverity = "high"
+	}
+	if cfg.IssueType == "" {
+		cfg.IssueType = "Bug"
+	}
+	c := jiraClient{cfg: cfg, http: &http.Client{Timeout: 60 * time.Second}}
+	var errs []error
+	for _, f := range AtLeast(findings, cfg.MinSeverity) {
+		open, err := c.hasOpenIssue(ctx, templateOf(f))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if open {
+			continue
+		}
+		key, err := c.create(ctx, f)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to file Jira issue for %s: %w", templateOf(f), err))
+			continue
+		}
+		if file := cfg.Attachments[f.ID]; file != "" {
+			if err := c.attach(ctx, key, file); err != nil {
+				errs = append(errs, fmt.Errorf("failed to attach %s to %s: %w", filepath.Base(file), key, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type jiraClient struct {
+	cfg  JiraConfig
+	http *http.Client
+}
+
+func (c jiraClient) hasOpenIssue(ctx context.Context, template string) (bool, error) {
+	jql := fmt.Sprintf(`project = "%s" AND labels = "%s" AND statusCategory != Done`, c.cfg.Project, JiraTemplateLabel(template))
+	q := url.Values{"jql": {jql}, "maxResults": {"1"}, "fields": {"key"}}
+	// Jira Cloud retired /rest/api/2/search; its replacement pages by token and
+	// reports no total, so both are checked for a returned issue
+	path := "/rest/api/2/search"
+	if c.cloud() {
+		path = "/rest/api/3/search/jql"
+	}
+	var res struct {
+		Issues []struct {
+			Key string `json:"key"`
+		} `json:"issues"`
+	}
+	if err := c.do(ctx, http.MethodGet, path+"?"+q.Encode(), nil, &res); err != nil {
+		return false, fmt.Errorf("failed to search Jira for %s: %w", template, err)
+	}
+	return len(res.Issues) > 0, nil
+}
+
+// cloud reports whether c talks to Jira Cloud, which authenticates with an
+// account email (see JiraConfig.User)
+func (c jiraClient) cloud() bool {
+	return c.cfg.User != ""
+}
+
+func (c jiraClient) create(ctx context.Context, f schema.Finding) (string, error) {
+	fields := map[string]any{
+		"project":     map[string]string{"key": c.cfg.Project},
+		"issuetype":   map[string]string{"name": c.cfg.IssueType},
+		"summary":     issueTitle(f),
+		"description": jiraDescription(f),
+		"labels":      []string{"yoro", "security", JiraTemplateLabel(templateOf(f))},
+	}
+	if p, ok := jiraPriority[strings.ToLower(f.Severity)]; ok {
+		fields["priority"] = map[string]string{"name": p}
+	}
+	var res struct {
+		Key string `json:"key"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]any{"fields": fields}, &res); err != nil {
+		return "", err
+	}
+	return res.Key, nil
+}
+
+func (c jiraClient) attach(ctx context.Context, key, file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", filepath.Base(file))
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url("/rest/api/2/issue/"+url.PathEscape(key)+"/attachments"), &body)
+	if err != nil {
+		return err
+	}
+	c.auth(req.Header)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("User-Agent", "yorosec-agent")
+	// Jira rejects attachment uploads without this XSRF opt-out
+	req.Header.Set("X-Atlassian-Token", "no-check")
+	return send(c.http, req, nil)
+}
+
+func (c jiraClient) do(ctx context.Context, method, path string, in, out any) error {
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	c.auth(header)
+	if err := sendJSON(ctx, c.http, method, c.url(path), header, in, out); err != nil {
+		return fmt.Errorf("Jira API %s %s: %w", method, strings.SplitN(path, "?", 2)[0], err)
+	}
+	return nil
+}
+
+func (c jiraClient) url(path string) string {
+	return strings.TrimRight(c.cfg.URL, "/") + path
+}
+
+func (c jiraClient) auth(h http.Header) {
+	if c.cloud() {
+		h.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.cfg.User+":"+c.cfg.Token)))
+		return
+	}
+	h.Set("Authorization", "Bearer "+c.cfg.Token)
+}
+
+// jiraDescription renders f in Jira wiki markup, which the v2 API expects
+func jiraDescription(f schema.Finding) string {
+	g := explain.Explain(f)
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Severity:* %s\n*Target:* %s\n*Template:* {{%s}}\n*Scanner:* %s\n", f.Severity, f.Target, templateOf(f), f.Scanner)
+	if f.CVSS > 0 {
+		fmt.Fprintf(&b, "*CVSS:* %.1f\n", f.CVSS)
+	}
+	if f.Description != "" {
+		fmt.Fprintf(&b, "\nh3. Description\n%s\n", f.Description)
+	}
+	if f.Evidence != "" {
+		fmt.Fprintf(&b, "\nh3. Evidence\n{noformat}\n%s\n{noformat}\n", strings.ReplaceAll(f.Evidence, "{noformat}", "{ noformat}"))
+	}
+	if g.Remediation != "" {
+		fmt.Fprintf(&b, "\nh3. Remediation\n%s\n", g.Remediation)
+	}
+	if len(g.References) > 0 {
+		b.WriteString("\nh3. References\n")
+		for _, r := range g.References {
+			fmt.Fprintf(&b, "* %s\n", r)
+		}
+	}
+	b.WriteString("\n_Filed by yorosec-agent._\n")
+	return b.String()
+}
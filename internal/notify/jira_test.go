@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// fakeJira answers issue searches and creation, with open issues for the labels
+// in open
+type fakeJira struct {
+	mu       sync.Mutex
+	open     map[string]bool
+	searches []string // request paths of searches
+	created  []string // labels of filed issues' templates
+}
+
+func (j *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/search"):
+		j.searches = append(j.searches, r.URL.Path)
+		issues := []map[string]string{}
+		for label := range j.open {
+			if strings.Contains(r.URL.Query().Get("jql"), `labels = "`+label+`"`) {
+				issues = append(issues, map[string]string{"key": "SEC-1"})
+			}
+		}
+		res := map[string]any{"issues": issues}
+		if r.URL.Path == "/rest/api/2/search" {
+			res["total"] = len(issues)
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+		var body struct {
+			Fields struct {
+				Labels []string `json:"labels"`
+			} `json:"fields"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		j.created = append(j.created, body.Fields.Labels[len(body.Fields.Labels)-1])
+		_ = json.NewEncoder(w).Encode(map[string]string{"key": "SEC-2"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestCreateJiraIssuesSearch(t *testing.T) {
+	findings := []schema.Finding{
+		{ID: "a", Template: "git-config", Severity: "high"},
+		{ID: "b", Template: "exposed-env", Severity: "critical"},
+	}
+	tests := []struct {
+		name       string
+		user       string
+		wantSearch string
+	}{
+		{"cloud", "secops@example.com", "/rest/api/3/search/jql"},
+		{"server", "", "/rest/api/2/search"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jira := &fakeJira{open: map[string]bool{JiraTemplateLabel("git-config"): true}}
+			srv := httptest.NewServer(jira)
+			defer srv.Close()
+
+			cfg := JiraConfig{URL: srv.URL, Project: "SEC", User: tt.user, Token: "t"}
+			if err := CreateJiraIssues(context.Background(), cfg, findings); err != nil {
+				t.Fatalf("CreateJiraIssues: %v", err)
+			}
+			if want := []string{tt.wantSearch, tt.wantSearch}; !slices.Equal(jira.searches, want) {
+				t.Errorf("searched %v, want %v", jira.searches, want)
+			}
+			// git-config has an open issue and is not filed again
+			if want := []string{JiraTemplateLabel("exposed-env")}; !slices.Equal(jira.created, want) {
+				t.Errorf("filed %v, want %v", jira.created, want)
+			}
+		})
+	}
+}
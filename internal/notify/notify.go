@@ -0,0 +1,129 @@
+// Package notify files new findings in external issue trackers and emails reports
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/explain"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// Serious returns the unsuppressed critical and high findings, one per template
+func Serious(findings []schema.Finding) []schema.Finding {
+	return AtLeast(findings, "high")
+}
+
+// AtLeast returns the unsuppressed findings of severity min or worse, one per
+// template: trackers get one issue per template, not one per affected URL
+func AtLeast(findings []schema.Finding, min string) []schema.Finding {
+	floor := schema.SeverityRank(min)
+	seen := map[string]bool{}
+	var out []schema.Finding
+	for _, f := range findings {
+		if f.Suppressed || schema.SeverityRank(f.Severity) < floor || seen[templateOf(f)] {
+			continue
+		}
+		seen[templateOf(f)] = true
+		out = append(out, f)
+	}
+	return out
+}
+
+// templateOf is the key issues are de-duplicated by
+func templateOf(f schema.Finding) string {
+	if f.Template != "" {
+		return f.Template
+	}
+	return f.ID
+}
+
+// issueTitle is "[HIGH] <template>: <description>", cut to a readable length
+func issueTitle(f schema.Finding) string {
+	title := fmt.Sprintf("[%s] %s", strings.ToUpper(f.Severity), templateOf(f))
+	if desc, _, _ := strings.Cut(strings.TrimSpace(f.Description), "\n"); desc != "" && desc != templateOf(f) {
+		title += ": " + desc
+	}
+	if r := []rune(title); len(r) > 120 {
+		title = string(r[:119]) + "…"
+	}
+	return title
+}
+
+// issueBody renders f as Markdown: description, evidence, remediation and references
+func issueBody(f schema.Finding) string {
+	g := explain.Explain(f)
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Severity:** %s  \n**Target:** %s  \n**Template:** `%s`  \n**Scanner:** %s\n", f.Severity, f.Target, templateOf(f), f.Scanner)
+	if f.CVSS > 0 {
+		fmt.Fprintf(&b, "**CVSS:** %.1f\n", f.CVSS)
+	}
+	if f.Description != "" {
+		fmt.Fprintf(&b, "\n### Description\n\n%s\n", f.Description)
+	}
+	if f.Evidence != "" {
+		fmt.Fprintf(&b, "\n### Evidence\n\n```\n%s\n```\n", strings.ReplaceAll(f.Evidence, "```", "'''"))
+	}
+	if g.Remediation != "" {
+		fmt.Fprintf(&b, "\n### Remediation\n\n%s\n", g.Remediation)
+	}
+	if len(g.References) > 0 {
+		b.WriteString("\n### References\n\n")
+		for _, r := range g.References {
+			fmt.Fprintf(&b, "- %s\n", r)
+		}
+	}
+	b.WriteString("\n_Filed by yorosec-agent._\n")
+	return b.String()
+}
+
+// sendJSON sends in (when non-nil) as a JSON request body and decodes a JSON
+// response into out (when non-nil); non-2xx answers become errors with the
+// start of the response body
+func sendJSON(ctx context.Context, hc *http.Client, method, url string, header http.Header, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("User-Agent", "yorosec-agent")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return send(hc, req, out)
+}
+
+// send runs req and decodes a JSON response into out when non-nil
+func send(hc *http.Client, req *http.Request, out any) error {
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+//go:embed templates/aggregate.html.tmpl
+var aggregateHTMLTemplate string
+
+// FindResultDirs returns every directory under root that holds a results.json
+func FindResultDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == "results.json" {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", root, err)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+type aggregateViewModel struct {
+	Lang          string
+	Title         string
+	Logo          template.URL
+	TotalTargets  int
+	TotalFindings int
+	NoFindings    bool     // clean: no findings and every scan complete
+	Incomplete    []string // targets whose scans had failed scanners
+	Severities    []severityView
+	Targets       []targetSummary
+	Groups        []findingGroup
+	Generator     string
+	GeneratedAt   string
+	Year          int
+}
+
+// targetSummary is one row of the per-target table
+type targetSummary struct {
+	Target   string
+	Labels   string
+	ScanTime string
+	Score    int
+	Grade    string
+	Counts   []int
+	Total    int
+	Failed   []string // scanners that did not finish
+}
+
+// latestPerTarget keeps only the most recent scan of each target and label set,
+// so prod and staging scans of one host are summarized separately
+func latestPerTarget(results []schema.ScanResult) []schema.ScanResult {
+	latest := map[string]schema.ScanResult{}
+	key := func(r schema.ScanResult) string { return r.Target + "|" + strings.Join(r.LabelPairs(), ",") }
+	for _, r := range results {
+		if cur, ok := latest[key(r)]; !ok || r.Timestamp.After(cur.Timestamp) {
+			latest[key(r)] = r
+		}
+	}
+	out := make([]schema.ScanResult, 0, len(latest))
+	for _, r := range latest {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return key(out[i]) < key(out[j]) })
+	return out
+}
+
+func buildAggregateViewModel(results []schema.ScanResult, opts Options, cat catalog) aggregateViewModel {
+	now := time.Now().UTC()
+	theme := cat.severityLabels(opts.theme())
+	results = latestPerTarget(results)
+
+	var all []schema.Finding
+	var targets []targetSummary
+	var incomplete []string
+	for _, r := range results {
+		score, grade := ScoreOf(r)
+		active, _ := splitSuppressed(redactFindings(r.Findings, opts.Redact))
+		ts := targetSummary{
+			Target:   r.Target,
+			Labels:   strings.Join(r.LabelPairs(), ", "),
+			ScanTime: r.Timestamp.UTC().Format(time.RFC3339),
+			Score:    score,
+			Grade:    grade,
+			Total:    len(active),
+			Failed:   r.FailedScanners(),
+		}
+		if len(ts.Failed) > 0 {
+			incomplete = append(incomplete, r.Target)
+		}
+		counts := map[string]int{}
+		for _, f := range active {
+			counts[SeverityOf(f)]++
+		}
+		for _, sev := range severityOrder {
+			ts.Counts = append(ts.Counts, counts[sev])
+		}
+		targets = append(targets, ts)
+		all = append(all, active...)
+	}
+	// Worst targets first
+	sort.SliceStable(targets, func(i, j int) bool { return targets[i].Score < targets[j].Score })
+
+	rows, counts := buildRows(all, theme)
+	title := strings.TrimSpace(opts.Title)
+	if title == "" {
+		title = cat.T("aggregate_title", len(results))
+	}
+	return aggregateViewModel{
+		Lang:          cat.T("lang"),
+		Title:         title,
+		TotalTargets:  len(results),
+		TotalFindings: len(all),
+		NoFindings:    len(all) == 0 && len(incomplete) == 0,
+		Incomplete:    incomplete,
+		Severities:    severityViews(counts, len(all), theme),
+		Targets:       targets,
+		Groups:        groupRows(rows, theme),
+		Generator:     "yorosec-agent",
+		GeneratedAt:   now.Format(time.RFC3339),
+		Year:          now.Year(),
+	}
+}
+
+// GenerateAggregateHTML renders one roll-up report for several scans (the latest
+// per target) and saves it to <outDir>/<opts.Name>.html (default aggregate.html)
+func GenerateAggregateHTML(results []schema.ScanResult, outDir string, opts Options) (string, error) {
+	cat, err := loadCatalog(opts.Lang)
+	if err != nil {
+		return "", err
+	}
+	vm := buildAggregateViewModel(results, opts, cat)
+	if opts.LogoPath != "" {
+		logo, err := loadLogo(opts.LogoPath)
+		if err != nil {
+			return "", err
+		}
+		vm.Logo = logo
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return "", fmt.Errorf("create out dir: %w", err)
+	}
+
+	tmpl, err := template.New("aggregate").Funcs(cat.funcs()).Parse(aggregateHTMLTemplate)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vm); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+
+	htmlPath := filepath.Join(outDir, fallback(opts.Name, "aggregate")+".html")
+	if err := checkSelfContained(filepath.Base(htmlPath), buf.Bytes()); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(htmlPath, buf.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("write %s: %w", filepath.Base(htmlPath), err)
+	}
+	return htmlPath, nil
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+// AnonymizeMapFile records which pseudonym stands for which host; it is for the
+// report's owners and must not be shared with the report
+const AnonymizeMapFile = "anonymize-map.json"
+
+// Anonymizer replaces target hosts with stable pseudonyms such as target-a1b2c3.
+// The pseudonym is a hash of the host, so the same host gets the same name in
+// every report; anyone who guesses a host can confirm it, which is fine for case
+// studies but not for hiding names from a determined reader.
+type Anonymizer struct {
+	names map[string]string // host -> pseudonym
+}
+
+// NewAnonymizer returns an Anonymizer with no hosts seen yet
+func NewAnonymizer() *Anonymizer {
+	return &Anonymizer{names: map[string]string{}}
+}
+
+// Result returns a copy of res with its target hosts, and every mention of them in
+// findings, replaced by pseudonyms; res itself is left untouched
+func (a *Anonymizer) Result(res schema.ScanResult) schema.ScanResult {
+	a.add(res.Target)
+	for _, f := range res.Findings {
+		a.add(f.Target)
+	}
+	hosts := make([]string, 0, len(a.names))
+	for h := range a.names {
+		hosts = append(hosts, h)
+	}
+	// Longest first, so a host is never partly replaced through a shorter one
+	sort.Slice(hosts, func(i, j int) bool { return len(hosts[i]) > len(hosts[j]) })
+	pairs := make([]string, 0, 2*len(hosts))
+	for _, h := range hosts {
+		pairs = append(pairs, h, a.names[h])
+	}
+	r := strings.NewReplacer(pairs...)
+
+	out := res
+	out.Target = r.Replace(res.Target)
+	out.Authorization = nil
+	out.ScannerMeta = make([]schema.ScannerMeta, len(res.ScannerMeta))
+	for i, m := range res.ScannerMeta {
+		m.Command, m.Error, m.Stderr = r.Replace(m.Command), r.Replace(m.Error), r.Replace(m.Stderr)
+		out.ScannerMeta[i] = m
+	}
+	out.Findings = make([]schema.Finding, len(res.Findings))
+	for i, f := range res.Findings {
+		f.Target = r.Replace(f.Target)
+		f.Description = r.Replace(f.Description)
+		f.Evidence = r.Replace(f.Evidence)
+		f.Recommendation = r.Replace(f.Recommendation)
+		if ex := f.RequestResponse; ex != nil {
+			f.RequestResponse = &schema.HTTPExchange{Request: r.Replace(ex.Request), Response: r.Replace(ex.Response), Truncated: ex.Truncated}
+		}
+		f.Raw = nil
+		out.Findings[i] = f
+	}
+	return out
+}
+
+// Mapping returns pseudonym -> real host for every host anonymized so far
+func (a *Anonymizer) Mapping() map[string]string {
+	m := make(map[string]string, len(a.names))
+	for host, name := range a.names {
+		m[name] = host
+	}
+	return m
+}
+
+// WriteMapping saves the mapping as <dir>/anonymize-map.json
+func (a *Anonymizer) WriteMapping(dir string) (string, error) {
+	data, err := json.MarshalIndent(a.Mapping(), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode %s: %w", AnonymizeMapFile, err)
+	}
+	path := filepath.Join(dir, AnonymizeMapFile)
+	if err := utils.WriteFileAtomic(path, append(data, '\n'), 0o600); err != nil {
+		return "", fmt.Errorf("write %s: %w", AnonymizeMapFile, err)
+	}
+	return path, nil
+}
+
+func (a *Anonymizer) add(target string) {
+	host := targetHost(target)
+	if host == "" || a.names[host] != "" {
+		return
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(host)))
+	a.names[host] = "target-" + hex.EncodeToString(sum[:3])
+}
+
+// targetHost returns the bare host of a URL or host[:port] target
+func targetHost(target string) string {
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	if h, _, err := net.SplitHostPort(target); err == nil {
+		return h
+	}
+	return target
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+// gradeColors are the shields.io palette entries for each grade
+var gradeColors = map[string]string{
+	"A": "#4c1",
+	"B": "#97ca00",
+	"C": "#dfb317",
+	"D": "#fe7d37",
+	"F": "#e05d44",
+}
+
+// GenerateBadge writes <outDir>/badge.svg, a shields.io-style "security: <grade>"
+// badge colored by the scan's grade, for pinning on wikis and READMEs
+func GenerateBadge(res schema.ScanResult, outDir string) (string, error) {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return "", fmt.Errorf("create out dir: %w", err)
+	}
+	score, grade := ScoreOf(res)
+	path := filepath.Join(outDir, "badge.svg")
+	svg := FormatBadge("security", grade, gradeColors[grade], fmt.Sprintf("security: %s (score %d/100)", grade, score))
+	if err := utils.WriteFileAtomic(path, []byte(svg), 0o644); err != nil {
+		return "", fmt.Errorf("write badge.svg: %w", err)
+	}
+	return path, nil
+}
+
+// FormatBadge renders a flat two-part badge; title is the hover text
+func FormatBadge(label, value, color, title string) string {
+	lw, vw := badgeTextWidth(label)+10, badgeTextWidth(value)+10
+	w := lw + vw
+	label, value, title = html.EscapeString(label), html.EscapeString(value), html.EscapeString(title)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[6]s">`+
+		`<title>%[6]s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[7]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[8]d" y="14">%[4]s</text>`+
+		`<text x="%[9]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text><text x="%[9]d" y="14">%[5]s</text>`+
+		`</g></svg>`+"\n",
+		w, lw, vw, label, value, title, color, lw/2, lw+vw/2)
+}
+
+// badgeTextWidth approximates the rendered width of s in 11px Verdana
+func badgeTextWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		switch {
+		case r == 'i' || r == 'l' || r == 'j' || r == ' ' || r == ':':
+			w += 4
+		case r >= 'A' && r <= 'Z' || r == 'm' || r == 'w':
+			w += 9
+		default:
+			w += 7
+		}
+	}
+	return w
+}
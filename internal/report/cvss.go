@@ -0,0 +1,80 @@
+package report
+
+import "strings"
+
+// cvssMetric names one CVSS v3 metric and its values
+type cvssMetric struct {
+	Name   string
+	Values map[string]string
+}
+
+var impactValues = map[string]string{"H": "High", "L": "Low", "N": "None"}
+
+// cvssMetrics are the CVSS v3.0/v3.1 base and temporal metrics by abbreviation
+var cvssMetrics = map[string]cvssMetric{
+	"AV": {"Attack Vector", map[string]string{"N": "Network", "A": "Adjacent", "L": "Local", "P": "Physical"}},
+	"AC": {"Attack Complexity", map[string]string{"L": "Low", "H": "High"}},
+	"PR": {"Privileges Required", map[string]string{"N": "None", "L": "Low", "H": "High"}},
+	"UI": {"User Interaction", map[string]string{"N": "None", "R": "Required"}},
+	"S":  {"Scope", map[string]string{"U": "Unchanged", "C": "Changed"}},
+	"C":  {"Confidentiality", impactValues},
+	"I":  {"Integrity", impactValues},
+	"A":  {"Availability", impactValues},
+
+	"E":  {"Exploit Code Maturity", withNotDefined(map[string]string{"H": "High", "F": "Functional", "P": "Proof-of-Concept", "U": "Unproven"})},
+	"RL": {"Remediation Level", withNotDefined(map[string]string{"U": "Unavailable", "W": "Workaround", "T": "Temporary Fix", "O": "Official Fix"})},
+	"RC": {"Report Confidence", withNotDefined(map[string]string{"C": "Confirmed", "R": "Reasonable", "U": "Unknown"})},
+}
+
+// cvssBaseMetrics must all appear in a valid vector
+var cvssBaseMetrics = []string{"AV", "AC", "PR", "UI", "S", "C", "I", "A"}
+
+// withNotDefined adds X, which every temporal metric allows
+func withNotDefined(values map[string]string) map[string]string {
+	values["X"] = "Not Defined"
+	return values
+}
+
+// cvssDetail spells out a CVSS v3 vector, one "Metric: Value" line per metric, for
+// the report's tooltip. Metrics it does not know (environmental ones) are listed as
+// written; a malformed vector comes back unchanged.
+func cvssDetail(vector string) string {
+	vector = strings.TrimSpace(vector)
+	lines, ok := parseCVSSVector(vector)
+	if !ok {
+		return vector
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parseCVSSVector(vector string) ([]string, bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) < 2 || (parts[0] != "CVSS:3.0" && parts[0] != "CVSS:3.1") {
+		return nil, false
+	}
+	lines := []string{"CVSS v" + strings.TrimPrefix(parts[0], "CVSS:")}
+	seen := map[string]bool{}
+	for _, p := range parts[1:] {
+		key, value, ok := strings.Cut(p, ":")
+		if !ok || key == "" || value == "" || seen[key] {
+			return nil, false
+		}
+		seen[key] = true
+		m, known := cvssMetrics[key]
+		if !known {
+			lines = append(lines, key+": "+value)
+			continue
+		}
+		label, valid := m.Values[value]
+		if !valid {
+			return nil, false
+		}
+		lines = append(lines, m.Name+": "+label)
+	}
+	for _, key := range cvssBaseMetrics {
+		if !seen[key] {
+			return nil, false
+		}
+	}
+	return lines, true
+}
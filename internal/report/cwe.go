@@ -0,0 +1,69 @@
+package report
+
+import (
+	_ "embed"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed data/cwe.tsv
+var cweTable string
+
+var (
+	cweOnce  sync.Once
+	cweNames map[int]string
+)
+
+// CWEName returns the name of a CWE ID such as 79 from the embedded table
+func CWEName(id int) (string, bool) {
+	cweOnce.Do(func() {
+		cweNames = map[int]string{}
+		for _, line := range strings.Split(cweTable, "\n") {
+			num, name, ok := strings.Cut(line, "\t")
+			if !ok || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if n, err := strconv.Atoi(num); err == nil {
+				cweNames[n] = strings.TrimSpace(name)
+			}
+		}
+	})
+	name, ok := cweNames[id]
+	return name, ok
+}
+
+// ParseCWETag returns the CWE number of a "cwe:79", "cwe:CWE-79" or "CWE-79" tag
+func ParseCWETag(tag string) (int, bool) {
+	s := strings.ToUpper(strings.TrimSpace(tag))
+	s, tagged := strings.CutPrefix(s, "CWE:")
+	s, prefixed := strings.CutPrefix(s, "CWE-")
+	if !tagged && !prefixed {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// cweLabels renders the CWE tags as "CWE-79: Cross-site Scripting", or just
+// "CWE-79" for IDs missing from the table
+func cweLabels(tags []string) []string {
+	var out []string
+	seen := map[int]bool{}
+	for _, t := range tags {
+		n, ok := ParseCWETag(t)
+		if !ok || seen[n] {
+			continue
+		}
+		seen[n] = true
+		label := "CWE-" + strconv.Itoa(n)
+		if name, ok := CWEName(n); ok {
+			label += ": " + name
+		}
+		out = append(out, label)
+	}
+	return out
+}
@@ -4,17 +4,23 @@ import (
 	"bytes"
 	"context"
 	_ "embed"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
+	"gopkg.in/yaml.v3"
 
 	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
 )
@@ -26,27 +32,104 @@ var reportHTMLTemplate string
 // Public API
 // ---------------------------------------------------------------------------
 
-// LoadScanResult reads results.json into a ScanResult
+// ErrIncompleteResult means a results file is empty or cut off, typically by a
+// scan that was killed while writing it
+var ErrIncompleteResult = errors.New("appears incomplete — the scan may have been interrupted")
+
+// LoadScanResult reads <fromDir>/results.json into a ScanResult, falling back to
+// results.yaml when there is no JSON file
 func LoadScanResult(fromDir string) (schema.ScanResult, error) {
+	file := filepath.Join(fromDir, "results.json")
+	if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+		if _, err := os.Stat(filepath.Join(fromDir, "results.yaml")); err == nil {
+			file = filepath.Join(fromDir, "results.yaml")
+		}
+	}
+	return LoadScanResultFile(file)
+}
+
+// LoadScanResultFile reads a results.json-format file, or its YAML form when the
+// name ends in .yaml or .yml, migrating older schema versions
+func LoadScanResultFile(file string) (schema.ScanResult, error) {
 	var res schema.ScanResult
-	data, err := os.ReadFile(filepath.Join(fromDir, "results.json"))
+	name := filepath.Base(file)
+	data, err := os.ReadFile(file)
 	if err != nil {
-		return res, fmt.Errorf("read results.json: %w", err)
+		return res, fmt.Errorf("read %s: %w", name, err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return res, fmt.Errorf("%s %w (the file is empty)", file, ErrIncompleteResult)
+	}
+	if ext := strings.ToLower(filepath.Ext(file)); ext == ".yaml" || ext == ".yml" {
+		if data, err = yamlToJSON(data); err != nil {
+			return res, fmt.Errorf("parse %s: %w", name, err)
+		}
 	}
 	if err := json.Unmarshal(data, &res); err != nil {
-		return res, fmt.Errorf("parse results.json: %w", err)
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			return res, fmt.Errorf("%s %w (%v at byte %d)", file, ErrIncompleteResult, err, syntax.Offset)
+		}
+		return res, fmt.Errorf("parse %s: %w", name, err)
+	}
+	if err := schema.Migrate(&res); err != nil {
+		return res, fmt.Errorf("%s: %w", file, err)
 	}
 	return res, nil
 }
 
-// GenerateHTML renders an HTML report and saves it to <outDir>/report.html
-func GenerateHTML(res schema.ScanResult, outDir string) (string, error) {
-	vm := buildViewModel(res)
+// yamlToJSON converts a YAML results file to JSON so it decodes through the same
+// struct tags as results.json
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// Options customizes report rendering; the zero value gives the default report
+type Options struct {
+	// Theme overrides severity colors and labels (DefaultTheme when empty)
+	Theme Theme
+	// Title replaces the default "Security Report — <target>" heading
+	Title string
+	// LogoPath is a PNG/JPEG/SVG inlined into the header as a data URI
+	LogoPath string
+	// Name is the report file's base name without extension (default "report")
+	Name string
+	// Redact masks matching substrings of descriptions and evidence (see CompileRedactions)
+	Redact []*regexp.Regexp
+	// Lang selects the catalog for the report's static labels (see Languages; default en)
+	Lang string
+}
+
+func (o Options) theme() Theme {
+	if len(o.Theme.Severities) == 0 {
+		return DefaultTheme()
+	}
+	return o.Theme
+}
+
+// GenerateHTML renders an HTML report and saves it to <outDir>/<opts.Name>.html
+func GenerateHTML(res schema.ScanResult, outDir string, opts Options) (string, error) {
+	cat, err := loadCatalog(opts.Lang)
+	if err != nil {
+		return "", err
+	}
+	vm := buildViewModel(res, opts, cat)
+	if opts.LogoPath != "" {
+		logo, err := loadLogo(opts.LogoPath)
+		if err != nil {
+			return "", err
+		}
+		vm.Logo = logo
+	}
 	if err := os.MkdirAll(outDir, 0o755); err != nil {
 		return "", fmt.Errorf("create out dir: %w", err)
 	}
 
-	tmpl, err := template.New("report").Parse(reportHTMLTemplate)
+	tmpl, err := template.New("report").Funcs(cat.funcs()).Parse(reportHTMLTemplate)
 	if err != nil {
 		return "", fmt.Errorf("parse template: %w", err)
 	}
@@ -56,28 +139,146 @@ func GenerateHTML(res schema.ScanResult, outDir string) (string, error) {
 		return "", fmt.Errorf("execute template: %w", err)
 	}
 
-	htmlPath := filepath.Join(outDir, "report.html")
+	htmlPath := filepath.Join(outDir, fallback(opts.Name, "report")+".html")
+	if err := checkSelfContained(filepath.Base(htmlPath), buf.Bytes()); err != nil {
+		return "", err
+	}
 	if err := os.WriteFile(htmlPath, buf.Bytes(), 0o644); err != nil {
-		return "", fmt.Errorf("write report.html: %w", err)
+		return "", fmt.Errorf("write %s: %w", filepath.Base(htmlPath), err)
 	}
 	return htmlPath, nil
 }
 
+// pdfFooter is Chrome's page footer template; it is rendered outside the page CSS,
+// so it needs its own inline styles
+func pdfFooter(cat catalog) string {
+	page := strings.NewReplacer("{page}", `<span class="pageNumber"></span>`, "{pages}", `<span class="totalPages"></span>`).
+		Replace(template.HTMLEscapeString(cat.T("pdf_page")))
+	return `<div style="width:100%;font-size:8px;color:#8aa0b5;padding:0 0.4in;display:flex;justify-content:space-between">` +
+		`<span class="title"></span><span>` + page + `</span></div>`
+}
+
+// PDFOptions configures the headless Chrome used for PDF output; the zero value
+// launches Chrome from PATH with chromedp's defaults
+type PDFOptions struct {
+	// ChromePath is the Chrome/Chromium binary to launch
+	ChromePath string
+	// NoSandbox disables Chrome's sandbox, which cannot start as root (e.g. in Docker).
+	// Without it a malicious page could escape the renderer, so only use it for trusted reports.
+	NoSandbox bool
+	// Flags are extra Chrome switches, "name" or "name=value", with or without leading dashes
+	Flags []string
+	// RemoteURL is the DevTools address (ws://host:9222) of an already running Chrome to
+	// render in instead of launching one; the launch options above are then ignored
+	RemoteURL string
+	// Lang is the language of the page footer (see Options.Lang)
+	Lang string
+}
+
+func (o PDFOptions) allocatorOptions() []chromedp.ExecAllocatorOption {
+	opts := append([]chromedp.ExecAllocatorOption{}, chromedp.DefaultExecAllocatorOptions[:]...)
+	if o.ChromePath != "" {
+		opts = append(opts, chromedp.ExecPath(o.ChromePath))
+	}
+	if o.NoSandbox {
+		opts = append(opts, chromedp.NoSandbox)
+	}
+	for _, f := range o.Flags {
+		name, value, ok := strings.Cut(strings.TrimLeft(strings.TrimSpace(f), "-"), "=")
+		if name == "" {
+			continue
+		}
+		if ok {
+			opts = append(opts, chromedp.Flag(name, value))
+		} else {
+			opts = append(opts, chromedp.Flag(name, true))
+		}
+	}
+	return opts
+}
+
 // GeneratePDF converts HTML report into PDF using headless Chrome (Chromedp)
-func GeneratePDF(htmlPath string) (string, error) {
-	ctx, cancel := chromedp.NewContext(context.Background())
-	defer cancel()
+func GeneratePDF(htmlPath string, opts PDFOptions) (string, error) {
+	r, err := NewPDFRenderer(opts)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	return r.Render(htmlPath)
+}
+
+// PDFRenderer keeps one Chrome running for many PDFs; each Render opens its own
+// tab, so it is safe for concurrent use
+type PDFRenderer struct {
+	cat     catalog
+	browser context.Context
+	cancel  func()
+}
 
+// NewPDFRenderer starts Chrome (or connects to opts.RemoteURL); Close stops it
+func NewPDFRenderer(opts PDFOptions) (*PDFRenderer, error) {
+	cat, err := loadCatalog(opts.Lang)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		actx        context.Context
+		cancelAlloc context.CancelFunc
+	)
+	if opts.RemoteURL != "" {
+		// Each report gets its own tab; cancelling closes the tabs, not the shared browser
+		actx, cancelAlloc = chromedp.NewRemoteAllocator(context.Background(), opts.RemoteURL)
+	} else {
+		actx, cancelAlloc = chromedp.NewExecAllocator(context.Background(), opts.allocatorOptions()...)
+	}
+	browser, cancelBrowser := chromedp.NewContext(actx)
+	r := &PDFRenderer{cat: cat, browser: browser, cancel: func() { cancelBrowser(); cancelAlloc() }}
+	// Start the browser now so tabs opened later share it
+	if err := chromedp.Run(browser); err != nil {
+		r.Close()
+		return nil, fmt.Errorf("chromedp PDF generation failed: %w", err)
+	}
+	return r, nil
+}
+
+// Close shuts down the browser, or disconnects from a remote one
+func (r *PDFRenderer) Close() { r.cancel() }
+
+// Render writes the PDF of htmlPath next to it and returns its path
+func (r *PDFRenderer) Render(htmlPath string) (string, error) {
+	html, err := os.ReadFile(htmlPath)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", filepath.Base(htmlPath), err)
+	}
+
+	ctx, cancel := chromedp.NewContext(r.browser)
+	defer cancel()
 	ctx, cancel = context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
+	// The report is self-contained, so load it as content: a remote Chrome cannot read local files
 	var buf []byte
-	err := chromedp.Run(ctx,
-		chromedp.Navigate("file://"+htmlPath),
+	err = chromedp.Run(ctx,
+		chromedp.Navigate("about:blank"),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			tree, err := page.GetFrameTree().Do(ctx)
+			if err != nil {
+				return err
+			}
+			return page.SetDocumentContent(tree.Frame.ID, string(html)).Do(ctx)
+		}),
+		// printToPDF fires no beforeprint event, so open collapsed sections here
+		chromedp.Evaluate(`document.querySelectorAll('details').forEach(function (d) { d.open = true; })`, nil),
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			var err error
 			buf, _, err = page.PrintToPDF().
 				WithPrintBackground(true).
+				WithDisplayHeaderFooter(true).
+				WithHeaderTemplate(`<span></span>`).
+				WithFooterTemplate(pdfFooter(r.cat)).
+				WithMarginTop(0.4).
+				WithMarginBottom(0.6).
 				Do(ctx)
 			return err
 		}),
@@ -98,94 +299,257 @@ func GeneratePDF(htmlPath string) (string, error) {
 // ---------------------------------------------------------------------------
 
 type viewModel struct {
-	Target         string
-	ScanTime       string
-	TotalFindings  int
-	Counts         map[string]int
-	Score          int
-	Grade          string
-	Findings       []findingRow
-	Generator      string
-	GeneratedAt    string
-	LegendSeverity []string
-	Year           int
+	Lang          string
+	Title         string
+	Logo          template.URL
+	Target        string
+	Labels        []string
+	ScanTime      string
+	Duration      string
+	Tools         string
+	TotalFindings int
+	NoFindings    bool     // clean: no findings and every scanner finished
+	Failed        []string // scanners that did not finish, so their findings are missing
+	Counts        map[string]int
+	Severities    []severityView
+	Score         int
+	Grade         string
+	RiskScore     int
+	MaxRiskScore  int
+	Groups        []findingGroup
+	Accepted      []findingRow // suppressed via the ignore file
+	Technologies  []techRow    // detected technologies, listed apart from the findings
+	Truncated     bool
+	MaxFindings   int
+	Generator     string
+	GeneratedAt   string
+	Year          int
+}
+
+// severityView carries the themed presentation and share of one severity
+type severityView struct {
+	Key     string // uppercase CSS class, e.g. CRITICAL
+	Label   string
+	Color   template.CSS
+	Count   int
+	Percent float64
+}
+
+// findingGroup is one collapsible per-severity section of the findings table
+type findingGroup struct {
+	Key    string
+	Label  string
+	Anchor string // element ID the table of contents links to
+	Count  int
+	Rows   []findingRow
+}
+
+// techRow is one detected technology in the Technologies section
+type techRow struct {
+	Target     string
+	Technology string
+	Category   string
+	Evidence   string
 }
 
 type findingRow struct {
+	Target      string
 	Severity    string
+	Rank        int // 4 (critical) to 0 (info), for client-side sorting
+	Label       string
 	ID          string
 	Template    string
+	CVSS        float64
+	CVSSDetail  string   // the vector spelled out, one metric per line, for the tooltip
+	CWEs        []string // e.g. "CWE-79: Cross-site Scripting"
 	Description string
 	Evidence    string
-	Scanner     string
+	Exchange    *schema.HTTPExchange
+	Scanners    []string
+	// SuppressedBy is the ignore rule ("file:line") that accepted the finding
+	SuppressedBy string
 }
 
-func buildViewModel(res schema.ScanResult) viewModel {
+func buildViewModel(res schema.ScanResult, opts Options, cat catalog) viewModel {
 	now := time.Now().UTC()
-	sevOrder := []string{"critical", "high", "medium", "low", "info"}
-	sevWeight := map[string]int{"critical": 4, "high": 3, "medium": 2, "low": 1, "info": 0}
+	theme := cat.severityLabels(opts.theme())
+	active, accepted := splitSuppressed(redactFindings(res.Findings, opts.Redact))
+	active, techs := splitTechnologies(active)
+	rows, counts := buildRows(active, theme)
+	acceptedRows, _ := buildRows(accepted, theme)
+	total := len(active)
+	score, grade := ScoreOf(res)
+	sevs := severityViews(counts, total, theme)
+	failed := res.FailedScanners()
+
+	title := strings.TrimSpace(opts.Title)
+	if title == "" {
+		title = cat.T("title", res.Target)
+	}
 
+	return viewModel{
+		Lang:          cat.T("lang"),
+		Title:         title,
+		Target:        res.Target,
+		Labels:        res.LabelPairs(),
+		ScanTime:      res.Timestamp.UTC().Format(time.RFC3339),
+		Duration:      formatDuration(res.Duration()),
+		Tools:         formatTools(res.ScannerMeta),
+		TotalFindings: total,
+		NoFindings:    total == 0 && len(failed) == 0,
+		Failed:        failed,
+		Counts:        normalizeCounts(counts, severityOrder),
+		Severities:    sevs,
+		Score:         score,
+		Grade:         grade,
+		RiskScore:     ComputeRiskScore(res.Findings),
+		MaxRiskScore:  MaxRiskScore,
+		Groups:        groupRows(rows, theme),
+		Accepted:      acceptedRows,
+		Technologies:  techRows(techs),
+		Truncated:     res.Truncated,
+		MaxFindings:   res.MaxFindings,
+		Generator:     "yorosec-agent",
+		GeneratedAt:   now.Format(time.RFC3339),
+		Year:          now.Year(),
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Helpers
+// ---------------------------------------------------------------------------
+
+// severityOrder is the display order of severities, most serious first
+var severityOrder = []string{"critical", "high", "medium", "low", "info"}
+
+// buildRows turns findings into table rows sorted by severity, then ID, and counts them per severity
+func buildRows(findings []schema.Finding, theme Theme) ([]findingRow, map[string]int) {
 	counts := map[string]int{}
 	var rows []findingRow
 
-	for _, f := range res.Findings {
-		sev := strings.ToLower(strings.TrimSpace(f.Severity))
-		if sev == "" {
-			sev = "info"
-		}
+	for _, f := range findings {
+		sev := SeverityOf(f)
 		counts[sev]++
 		rows = append(rows, findingRow{
+			Target:      f.Target,
 			Severity:    strings.ToUpper(sev),
+			Rank:        schema.SeverityRank(sev),
+			Label:       theme.style(sev).Label,
 			ID:          fallback(f.ID, "N/A"),
 			Template:    fallback(f.Template, "-"),
+			CVSS:        f.CVSS,
+			CVSSDetail:  cvssDetail(f.CVSSVector),
+			CWEs:        cweLabels(f.Tags),
 			Description: truncate(f.Description, 500),
 			Evidence:    truncate(f.Evidence, 200),
-			Scanner:     f.Scanner,
+			Exchange:    f.RequestResponse,
+			Scanners:    strings.Split(f.Scanner, ","),
+
+			SuppressedBy: f.SuppressedBy,
 		})
 	}
 
 	// Sort by severity, then by ID
 	sort.SliceStable(rows, func(i, j int) bool {
-		ai := indexOf(sevOrder, strings.ToLower(rows[i].Severity))
-		bi := indexOf(sevOrder, strings.ToLower(rows[j].Severity))
+		ai := indexOf(severityOrder, strings.ToLower(rows[i].Severity))
+		bi := indexOf(severityOrder, strings.ToLower(rows[j].Severity))
 		if ai != bi {
 			return ai < bi
 		}
+		if rows[i].Severity != rows[j].Severity {
+			return rows[i].Severity < rows[j].Severity
+		}
 		return rows[i].ID < rows[j].ID
 	})
+	return rows, counts
+}
 
-	// Simple score heuristic based on weighted severity counts
-	total := 0
-	weighted := 0
-	for sev, c := range counts {
-		total += c
-		weighted += sevWeight[sev] * c
+// severityViews pairs each severity's themed style with its count and share of total
+func severityViews(counts map[string]int, total int, theme Theme) []severityView {
+	var sevs []severityView
+	for _, sev := range severityOrder {
+		style := theme.style(sev)
+		sv := severityView{Key: strings.ToUpper(sev), Label: style.Label, Color: style.css(), Count: counts[sev]}
+		if total > 0 {
+			sv.Percent = float64(counts[sev]) * 100 / float64(total)
+		}
+		sevs = append(sevs, sv)
 	}
-	score := 100
-	if total > 0 {
-		penalty := min(100, (weighted*100)/(total*4)) // normalize
-		score = 100 - penalty
+	return sevs
+}
+
+// groupRows splits severity-sorted rows into consecutive per-severity groups
+func groupRows(rows []findingRow, theme Theme) []findingGroup {
+	var groups []findingGroup
+	for _, r := range rows {
+		if n := len(groups); n > 0 && groups[n-1].Key == r.Severity {
+			groups[n-1].Rows = append(groups[n-1].Rows, r)
+			groups[n-1].Count++
+			continue
+		}
+		groups = append(groups, findingGroup{
+			Key:    r.Severity,
+			Label:  theme.style(strings.ToLower(r.Severity)).Label,
+			Anchor: "findings-" + strings.ToLower(r.Severity),
+			Count:  1,
+			Rows:   []findingRow{r},
+		})
 	}
-	grade := scoreToGrade(score)
+	return groups
+}
 
-	return viewModel{
-		Target:         res.Target,
-		ScanTime:       res.Timestamp.UTC().Format(time.RFC3339),
-		TotalFindings:  total,
-		Counts:         normalizeCounts(counts, sevOrder),
-		Score:          score,
-		Grade:          grade,
-		Findings:       rows,
-		Generator:      "yorosec-agent",
-		GeneratedAt:    now.Format(time.RFC3339),
-		LegendSeverity: []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "INFO"},
-		Year:           now.Year(),
+// splitTechnologies separates technology fingerprints from the findings that
+// go into the severity tables and counts
+func splitTechnologies(findings []schema.Finding) (rest, techs []schema.Finding) {
+	for _, f := range findings {
+		if slices.Contains(f.Tags, schema.TechnologyTag) {
+			techs = append(techs, f)
+		} else {
+			rest = append(rest, f)
+		}
 	}
+	return rest, techs
 }
 
-// ---------------------------------------------------------------------------
-// Helpers
-// ---------------------------------------------------------------------------
+func techRows(findings []schema.Finding) []techRow {
+	rows := make([]techRow, 0, len(findings))
+	for _, f := range findings {
+		category := "-"
+		for _, t := range f.Tags {
+			if c, ok := strings.CutPrefix(t, "category:"); ok {
+				category = strings.ReplaceAll(c, "-", " ")
+			}
+		}
+		rows = append(rows, techRow{
+			Target:     f.Target,
+			Technology: strings.TrimPrefix(f.Description, "Detected: "),
+			Category:   category,
+			Evidence:   truncate(f.Evidence, 200),
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Category < rows[j].Category })
+	return rows
+}
+
+// loadLogo reads an image and returns it as a data URI so reports stay self-contained
+func loadLogo(path string) (template.URL, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read logo: %w", err)
+	}
+	mime := http.DetectContentType(data)
+	switch {
+	case mime == "image/png", mime == "image/jpeg":
+	case strings.EqualFold(filepath.Ext(path), ".svg") && bytes.Contains(data, []byte("<svg")):
+		mime = "image/svg+xml"
+		if err := checkSVGSelfContained("logo "+path, data); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("logo %s is not a PNG, JPEG or SVG image (detected %s)", path, mime)
+	}
+	return template.URL("data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data)), nil
+}
 
 func indexOf(arr []string, v string) int {
 	for i, x := range arr {
@@ -196,21 +560,6 @@ func indexOf(arr []string, v string) int {
 	return len(arr)
 }
 
-func scoreToGrade(score int) string {
-	switch {
-	case score >= 90:
-		return "A"
-	case score >= 80:
-		return "B"
-	case score >= 70:
-		return "C"
-	case score >= 60:
-		return "D"
-	default:
-		return "F"
-	}
-}
-
 func normalizeCounts(in map[string]int, order []string) map[string]int {
 	out := make(map[string]int, len(order))
 	for _, k := range order {
@@ -227,16 +576,33 @@ func truncate(s string, n int) string {
 	return s[:n] + "…"
 }
 
+// formatTools lists the scanner versions recorded in the result, e.g. "nuclei v3.3.0, zap (failed)"
+func formatTools(meta []schema.ScannerMeta) string {
+	var parts []string
+	for _, m := range meta {
+		part := strings.TrimSpace(m.Name + " " + m.Version)
+		if m.Status == schema.ScannerFailed {
+			part += " (failed)"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDuration renders a scan duration, or "" when it was not recorded
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
 func fallback(s, fb string) string {
 	if strings.TrimSpace(s) == "" {
 		return fb
 	}
 	return s
 }
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
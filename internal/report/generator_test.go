@@ -0,0 +1,134 @@
+package report
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+func renderReport(t *testing.T, res schema.ScanResult, lang string) string {
+	t.Helper()
+	path, err := GenerateHTML(res, t.TempDir(), Options{Lang: lang})
+	if err != nil {
+		t.Fatalf("GenerateHTML: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestGenerateHTMLIncompleteScanIsNotClean(t *testing.T) {
+	res := schema.ScanResult{
+		Target:    "https://example.com",
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ScannerMeta: []schema.ScannerMeta{
+			{Name: "nuclei", Status: schema.ScannerFailed, Error: "stopped by --timeout: nuclei failed: signal: killed", ExitCode: -1},
+			{Name: "headers", Status: schema.ScannerFailed, Error: "headers timed out after 30s (raise it with --scanner-timeout): context deadline exceeded"},
+		},
+	}
+	for _, lang := range []string{"en", "ja"} {
+		t.Run(lang, func(t *testing.T) {
+			cat := mustCatalog(t, lang)
+			html := renderReport(t, res, lang)
+			if strings.Contains(html, cat.T("clean_title")) {
+				t.Error("a scan in which no scanner finished renders as clean")
+			}
+			if want := cat.T("incomplete", "nuclei, headers"); !strings.Contains(html, want) {
+				t.Errorf("report does not say the scan is incomplete (%q)", want)
+			}
+		})
+	}
+
+	vm := buildViewModel(res, Options{}, mustCatalog(t, "en"))
+	if vm.NoFindings {
+		t.Error("NoFindings is set although every scanner failed")
+	}
+}
+
+func TestGenerateHTMLCleanScan(t *testing.T) {
+	res := schema.ScanResult{
+		Target:      "https://example.com",
+		Timestamp:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ScannerMeta: []schema.ScannerMeta{{Name: "nuclei", Status: schema.ScannerOK}},
+	}
+	cat := mustCatalog(t, "en")
+	html := renderReport(t, res, "en")
+	if !strings.Contains(html, cat.T("clean_title")) {
+		t.Error("a completed scan without findings does not render as clean")
+	}
+	if strings.Contains(html, cat.T("incomplete", "nuclei")) {
+		t.Error("a completed scan renders as incomplete")
+	}
+}
+
+func mustCatalog(t *testing.T, lang string) catalog {
+	t.Helper()
+	cat, err := loadCatalog(lang)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cat
+}
+
+func TestGenerateHTMLPartialFailureWarns(t *testing.T) {
+	res := fixtureResult("https://example.com")
+	res.ScannerMeta = []schema.ScannerMeta{
+		{Name: "nuclei", Status: schema.ScannerFailed, Error: "nuclei failed: exit status 1"},
+		{Name: "headers", Status: schema.ScannerOK},
+	}
+	cat := mustCatalog(t, "en")
+	html := renderReport(t, res, "en")
+	if !strings.Contains(html, cat.T("incomplete", "nuclei")) {
+		t.Error("report with a failed scanner has no incomplete warning")
+	}
+	if !strings.Contains(html, "git-config") {
+		t.Error("findings of the scanners that finished are missing")
+	}
+	if vm := buildViewModel(res, Options{}, cat); len(vm.Failed) != 1 || vm.Failed[0] != "nuclei" {
+		t.Errorf("Failed = %v, want [nuclei]", vm.Failed)
+	}
+}
+
+func TestAggregateViewModelIncomplete(t *testing.T) {
+	clean := schema.ScanResult{
+		Target:      "https://example.org",
+		Timestamp:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ScannerMeta: []schema.ScannerMeta{{Name: "nuclei", Status: schema.ScannerOK}},
+	}
+	failed := clean
+	failed.Target = "https://example.com"
+	failed.ScannerMeta = []schema.ScannerMeta{{Name: "nuclei", Status: schema.ScannerFailed, Error: "stopped by --timeout"}}
+
+	cat := mustCatalog(t, "en")
+	vm := buildAggregateViewModel([]schema.ScanResult{clean, failed}, Options{}, cat)
+	if vm.NoFindings {
+		t.Error("NoFindings is set although one scan is incomplete")
+	}
+	if len(vm.Incomplete) != 1 || vm.Incomplete[0] != failed.Target {
+		t.Errorf("Incomplete = %v, want [%s]", vm.Incomplete, failed.Target)
+	}
+	if vm := buildAggregateViewModel([]schema.ScanResult{clean}, Options{}, cat); !vm.NoFindings || vm.Incomplete != nil {
+		t.Errorf("complete scans without findings: NoFindings %v, Incomplete %v", vm.NoFindings, vm.Incomplete)
+	}
+
+	path, err := GenerateAggregateHTML([]schema.ScanResult{clean, failed}, t.TempDir(), Options{})
+	if err != nil {
+		t.Fatalf("GenerateAggregateHTML: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	if !strings.Contains(html, cat.T("aggregate_incomplete", failed.Target)) {
+		t.Error("aggregate report has no incomplete warning")
+	}
+	if strings.Contains(html, cat.T("no_findings_any")) {
+		t.Error("aggregate report with an incomplete scan claims no findings for any target")
+	}
+}
@@ -0,0 +1,100 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+// HistoryFile is the index of every scan saved under an output root
+const HistoryFile = "index.json"
+
+// History is the content of index.json, oldest scan first
+type History struct {
+	Scans []HistoryEntry `json:"scans"`
+}
+
+// HistoryEntry summarizes one saved scan; Dir is relative to the output root
+type HistoryEntry struct {
+	Target    string            `json:"target"`
+	Dir       string            `json:"dir"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+	Score     int               `json:"score"`
+	Grade     string            `json:"grade"`
+	Counts    map[string]int    `json:"counts"`
+	Total     int               `json:"total"`
+}
+
+// historyMu serializes index updates from concurrent scans in one process
+var historyMu sync.Mutex
+
+// AppendHistory adds the scan saved in dir to <root>/index.json; the index is
+// rewritten atomically, so readers never see a partial file
+func AppendHistory(root, dir string, res schema.ScanResult) error {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	h, err := LoadHistory(root)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		rel = dir
+	}
+	s := Summarize(res)
+	h.Scans = append(h.Scans, HistoryEntry{
+		Target:    res.Target,
+		Dir:       filepath.ToSlash(rel),
+		Labels:    res.Labels,
+		Timestamp: res.Timestamp,
+		Score:     s.Score,
+		Grade:     s.Grade,
+		Counts:    s.Counts,
+		Total:     s.Total,
+	})
+	sort.SliceStable(h.Scans, func(i, j int) bool { return h.Scans[i].Timestamp.Before(h.Scans[j].Timestamp) })
+
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", HistoryFile, err)
+	}
+	if err := utils.WriteFileAtomic(filepath.Join(root, HistoryFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", HistoryFile, err)
+	}
+	return nil
+}
+
+// LoadHistory reads <root>/index.json; the error wraps os.ErrNotExist when no
+// scan has been indexed there yet
+func LoadHistory(root string) (History, error) {
+	var h History
+	data, err := os.ReadFile(filepath.Join(root, HistoryFile))
+	if err != nil {
+		return h, fmt.Errorf("read %s: %w", HistoryFile, err)
+	}
+	if err := json.Unmarshal(data, &h); err != nil {
+		return h, fmt.Errorf("parse %s: %w", filepath.Join(root, HistoryFile), err)
+	}
+	return h, nil
+}
+
+// ForTarget returns the entries of target, oldest first
+func (h History) ForTarget(target string) []HistoryEntry {
+	var out []HistoryEntry
+	for _, e := range h.Scans {
+		if e.Target == target {
+			out = append(out, e)
+		}
+	}
+	return out
+}
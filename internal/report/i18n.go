@@ -0,0 +1,99 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLang is the report language when none is chosen
+const DefaultLang = "en"
+
+//go:embed i18n/*.yaml
+var catalogFS embed.FS
+
+// catalog holds the translated static labels of the report templates
+type catalog map[string]string
+
+// Languages lists the languages reports can be rendered in
+func Languages() []string {
+	entries, _ := catalogFS.ReadDir("i18n")
+	var langs []string
+	for _, e := range entries {
+		langs = append(langs, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// loadCatalog returns the messages for lang layered over English
+func loadCatalog(lang string) (catalog, error) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		lang = DefaultLang
+	}
+	cat := catalog{}
+	for _, l := range []string{DefaultLang, lang} {
+		data, err := catalogFS.ReadFile("i18n/" + l + ".yaml")
+		if err != nil {
+			return nil, fmt.Errorf("unsupported report language %q (available: %s)", lang, strings.Join(Languages(), ", "))
+		}
+		var msgs map[string]string
+		if err := yaml.Unmarshal(data, &msgs); err != nil {
+			return nil, fmt.Errorf("parse %s catalog: %w", l, err)
+		}
+		for k, v := range msgs {
+			cat[k] = v
+		}
+	}
+	return cat, nil
+}
+
+// ValidateLang reports an error for languages without a catalog
+func ValidateLang(lang string) error {
+	_, err := loadCatalog(lang)
+	return err
+}
+
+// T formats the message key with args; unknown keys render as the key itself
+func (c catalog) T(key string, args ...any) string {
+	msg, ok := c[key]
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// N formats key_one or key_other for the count n
+func (c catalog) N(key string, n int) string {
+	if n == 1 {
+		return c.T(key+"_one", n)
+	}
+	return c.T(key+"_other", n)
+}
+
+func (c catalog) funcs() template.FuncMap {
+	return template.FuncMap{"t": c.T, "tn": c.N, "join": strings.Join}
+}
+
+// severityLabels swaps theme labels still at their English defaults for the
+// catalog's, so a --theme label always wins over the language
+func (c catalog) severityLabels(theme Theme) Theme {
+	defaults := DefaultTheme()
+	out := Theme{Severities: make(map[string]SeverityStyle, len(theme.Severities))}
+	for sev, style := range theme.Severities {
+		if msg, ok := c["severity."+sev]; ok && style.Label == defaults.Severities[sev].Label {
+			style.Label = msg
+		}
+		out.Severities[sev] = style
+	}
+	return out
+}
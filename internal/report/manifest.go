@@ -0,0 +1,79 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+// ManifestFile indexes the artifacts written to a result directory
+const ManifestFile = "manifest.json"
+
+// Manifest lists the files scan and report runs produced, so automation does not
+// have to guess file names
+type Manifest struct {
+	UpdatedAt time.Time  `json:"updated_at"`
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// Artifact is one generated file; Path is relative to the manifest's directory
+type Artifact struct {
+	Path   string `json:"path"`
+	Format string `json:"format"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// UpdateManifest records files (format -> path) in <dir>/manifest.json, keeping the
+// entries of earlier runs for other files and refreshing sizes of the given ones
+func UpdateManifest(dir string, files map[string]string) (string, error) {
+	path := filepath.Join(dir, ManifestFile)
+	var m Manifest
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &m); err != nil {
+			return "", fmt.Errorf("parse %s: %w", ManifestFile, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return "", fmt.Errorf("read %s: %w", ManifestFile, err)
+	}
+
+	byPath := map[string]Artifact{}
+	for _, a := range m.Artifacts {
+		byPath[a.Path] = a
+	}
+	for format, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", fmt.Errorf("stat %s: %w", filepath.Base(file), err)
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			rel = file
+		}
+		rel = filepath.ToSlash(rel)
+		byPath[rel] = Artifact{Path: rel, Format: format, Bytes: info.Size()}
+	}
+
+	m.UpdatedAt = time.Now().UTC()
+	m.Artifacts = m.Artifacts[:0]
+	for _, a := range byPath {
+		m.Artifacts = append(m.Artifacts, a)
+	}
+	sort.Slice(m.Artifacts, func(i, j int) bool { return m.Artifacts[i].Path < m.Artifacts[j].Path })
+
+	out, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode %s: %w", ManifestFile, err)
+	}
+	if err := utils.WriteFileAtomic(path, append(out, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("write %s: %w", ManifestFile, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// metricSeverities are always written, so a severity dropping to zero shows as 0
+var metricSeverities = []string{"critical", "high", "medium", "low", "info"}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// FormatMetrics renders results in the Prometheus text exposition format, as read
+// by the node_exporter textfile collector. Suppressed findings are not counted.
+func FormatMetrics(results []schema.ScanResult) []byte {
+	var b strings.Builder
+	b.WriteString("# HELP yoro_findings_total Unsuppressed findings of the last scan by severity\n")
+	b.WriteString("# TYPE yoro_findings_total gauge\n")
+	for _, res := range results {
+		counts := map[string]int{}
+		for _, f := range res.Findings {
+			if !f.Suppressed {
+				counts[SeverityOf(f)]++
+			}
+		}
+		for _, sev := range metricSeverities {
+			fmt.Fprintf(&b, "yoro_findings_total%s %d\n", metricLabels(res, "severity", sev), counts[sev])
+		}
+	}
+
+	b.WriteString("# HELP yoro_score Security score (0-100) of the last scan\n")
+	b.WriteString("# TYPE yoro_score gauge\n")
+	for _, res := range results {
+		score, _ := ScoreOf(res)
+		fmt.Fprintf(&b, "yoro_score%s %d\n", metricLabels(res), score)
+	}
+
+	b.WriteString("# HELP yoro_scan_duration_seconds Wall time of the last scan\n")
+	b.WriteString("# TYPE yoro_scan_duration_seconds gauge\n")
+	for _, res := range results {
+		fmt.Fprintf(&b, "yoro_scan_duration_seconds%s %g\n", metricLabels(res), res.Duration().Seconds())
+	}
+
+	b.WriteString("# HELP yoro_scan_timestamp_seconds Unix time the last scan finished\n")
+	b.WriteString("# TYPE yoro_scan_timestamp_seconds gauge\n")
+	for _, res := range results {
+		end := res.FinishedAt
+		if end.IsZero() {
+			end = res.Timestamp
+		}
+		fmt.Fprintf(&b, "yoro_scan_timestamp_seconds%s %d\n", metricLabels(res), end.Unix())
+	}
+	return []byte(b.String())
+}
+
+// metricLabels renders {target="...",<scan labels>,<extra pairs>}; scan label keys
+// are sanitized to valid Prometheus label names
+func metricLabels(res schema.ScanResult, extra ...string) string {
+	labels := map[string]string{"target": res.Target}
+	for k, v := range res.Labels {
+		name := invalidLabelChars.ReplaceAllString(k, "_")
+		if name == "" || name[0] >= '0' && name[0] <= '9' {
+			name = "_" + name
+		}
+		if _, taken := labels[name]; !taken && name != "severity" {
+			labels[name] = v
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		labels[extra[i]] = extra[i+1]
+	}
+
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, k := range names {
+		pairs[i] = k + `="` + labelValueEscaper.Replace(labels[k]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// labelValueEscaper applies the exposition format's label value escapes
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,26 @@
+package report
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+// ExpandReportName fills a --report-name pattern for res and makes it file-name safe.
+// Placeholders: {target} (without scheme), {date} (scan date, YYYYMMDD), {score} and {grade}.
+func ExpandReportName(pattern string, res schema.ScanResult) string {
+	score, grade := ScoreOf(res)
+	target := res.Target
+	if _, rest, ok := strings.Cut(target, "://"); ok {
+		target = rest
+	}
+	name := strings.NewReplacer(
+		"{target}", target,
+		"{date}", res.Timestamp.Format("20060102"),
+		"{score}", strconv.Itoa(score),
+		"{grade}", grade,
+	).Replace(pattern)
+	return utils.SafeName(strings.TrimSpace(name))
+}
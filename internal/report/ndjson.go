@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// GenerateNDJSON writes <outDir>/findings.ndjson: one JSON object per line for each
+// finding, with the scan timestamp and the scan's labels (as label_<key>) flattened in
+// so SIEM pipelines can ingest it without a transform step
+func GenerateNDJSON(res schema.ScanResult, outDir string) (string, error) {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return "", fmt.Errorf("create out dir: %w", err)
+	}
+	path := filepath.Join(outDir, "findings.ndjson")
+	fh, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("create findings.ndjson: %w", err)
+	}
+	defer fh.Close()
+
+	w := bufio.NewWriter(fh)
+	enc := json.NewEncoder(w)
+	for _, f := range res.Findings {
+		rec, err := ndjsonRecord(f, res)
+		if err != nil {
+			return "", err
+		}
+		if err := enc.Encode(rec); err != nil {
+			return "", fmt.Errorf("write findings.ndjson: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", fmt.Errorf("write findings.ndjson: %w", err)
+	}
+	if err := fh.Close(); err != nil {
+		return "", fmt.Errorf("write findings.ndjson: %w", err)
+	}
+	return path, nil
+}
+
+// ndjsonRecord flattens a finding and its scan context into one object
+func ndjsonRecord(f schema.Finding, res schema.ScanResult) (map[string]any, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, fmt.Errorf("encode finding %s: %w", f.ID, err)
+	}
+	var rec map[string]any
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("encode finding %s: %w", f.ID, err)
+	}
+	if f.Target == "" {
+		rec["target"] = res.Target
+	}
+	rec["scan_timestamp"] = res.Timestamp.UTC().Format(time.RFC3339)
+	for k, v := range res.Labels {
+		rec["label_"+k] = v
+	}
+	return rec, nil
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// redactedText replaces every match of a redaction pattern
+const redactedText = "[REDACTED]"
+
+// DefaultRedactPatterns mask common secrets and PII: JWTs, bearer tokens, AWS access keys and emails
+var DefaultRedactPatterns = []string{
+	`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`,
+	`(?i)bearer\s+[A-Za-z0-9._~+/-]+=*`,
+	`\b(AKIA|ASIA)[0-9A-Z]{16}\b`,
+	`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+}
+
+// CompileRedactions compiles redaction patterns for Options.Redact
+func CompileRedactions(patterns []string) ([]*regexp.Regexp, error) {
+	var out []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+// redactFindings returns a copy of findings with pattern matches in Description,
+// Evidence and the HTTP exchange masked; the caller's findings (and results.json)
+// are left untouched
+func redactFindings(findings []schema.Finding, patterns []*regexp.Regexp) []schema.Finding {
+	if len(patterns) == 0 {
+		return findings
+	}
+	out := make([]schema.Finding, len(findings))
+	for i, f := range findings {
+		var ex *schema.HTTPExchange
+		if f.RequestResponse != nil {
+			copied := *f.RequestResponse
+			ex = &copied
+		}
+		for _, re := range patterns {
+			f.Description = re.ReplaceAllString(f.Description, redactedText)
+			f.Evidence = re.ReplaceAllString(f.Evidence, redactedText)
+			if ex != nil {
+				ex.Request = re.ReplaceAllString(ex.Request, redactedText)
+				ex.Response = re.ReplaceAllString(ex.Response, redactedText)
+			}
+		}
+		f.RequestResponse = ex
+		out[i] = f
+	}
+	return out
+}
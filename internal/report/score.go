@@ -0,0 +1,120 @@
+package report
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// sevPenalty is the absolute number of points each finding costs
+var sevPenalty = map[string]int{"critical": 15, "high": 10, "medium": 5, "low": 2, "info": 0}
+
+// DefaultScoreWeights returns a copy of the built-in per-severity penalties
+func DefaultScoreWeights() map[string]int {
+	return maps.Clone(sevPenalty)
+}
+
+// ScoreWeights layers overrides (config: score-weights) over the default penalties;
+// only the five severities are accepted and no weight may be negative
+func ScoreWeights(overrides map[string]int) (map[string]int, error) {
+	weights := DefaultScoreWeights()
+	for sev, w := range overrides {
+		key := strings.ToLower(strings.TrimSpace(sev))
+		if _, ok := sevPenalty[key]; !ok {
+			return nil, fmt.Errorf("unknown severity %q in score-weights (expected critical, high, medium, low or info)", sev)
+		}
+		if w < 0 {
+			return nil, fmt.Errorf("score-weights: %s weight must not be negative, got %d", key, w)
+		}
+		weights[key] = w
+	}
+	return weights, nil
+}
+
+// ScoreOf scores a result with the weights it was scanned with, so the grade
+// reproduces even after the configured weights change
+func ScoreOf(res schema.ScanResult) (int, string) {
+	return ComputeScore(res.Findings, res.ScoreWeights)
+}
+
+// ComputeScore returns a 0–100 score and A–F grade. Every finding subtracts a fixed
+// per-severity penalty (weights, falling back to the defaults per severity), so adding
+// a finding never raises the score. Suppressed findings (accepted risks) cost nothing.
+func ComputeScore(findings []schema.Finding, weights map[string]int) (int, string) {
+	score := 100
+	for _, f := range findings {
+		if f.Suppressed {
+			continue
+		}
+		sev := SeverityOf(f)
+		if w, ok := weights[sev]; ok {
+			score -= w
+		} else {
+			score -= sevPenalty[sev]
+		}
+	}
+	if score < 0 {
+		score = 0
+	}
+	return score, scoreToGrade(score)
+}
+
+// riskWeight is what each finding adds to the risk score; unlike the grade's
+// penalties they grow steeply, so a few criticals outweigh many lows
+var riskWeight = map[string]int{"critical": 100, "high": 40, "medium": 10, "low": 2, "info": 0}
+
+// MaxRiskScore caps ComputeRiskScore
+const MaxRiskScore = 1000
+
+// ComputeRiskScore sums weighted severities into a 0–MaxRiskScore risk number. The
+// grade bottoms out after a handful of serious findings; this keeps growing with
+// their absolute number, for tracking exposure. Suppressed findings add nothing.
+func ComputeRiskScore(findings []schema.Finding) int {
+	risk := 0
+	for _, f := range findings {
+		if f.Suppressed {
+			continue
+		}
+		risk += riskWeight[SeverityOf(f)]
+	}
+	return min(risk, MaxRiskScore)
+}
+
+// splitSuppressed separates active findings from suppressed (accepted) ones
+func splitSuppressed(findings []schema.Finding) (active, accepted []schema.Finding) {
+	for _, f := range findings {
+		if f.Suppressed {
+			accepted = append(accepted, f)
+		} else {
+			active = append(active, f)
+		}
+	}
+	return active, accepted
+}
+
+// SeverityOf returns the finding's severity normalized by schema.NormalizeSeverity;
+// a finding without one is rated by its CVSS score, or info when it has none
+func SeverityOf(f schema.Finding) string {
+	sev := schema.NormalizeSeverity(f.Severity)
+	if sev == "" {
+		return schema.CVSSSeverity(f.CVSS)
+	}
+	return sev
+}
+
+func scoreToGrade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
@@ -0,0 +1,134 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+var severities = []string{"critical", "high", "medium", "low", "info"}
+
+func findingsOf(sevs ...string) []schema.Finding {
+	var findings []schema.Finding
+	for _, sev := range sevs {
+		findings = append(findings, schema.Finding{ID: sev, Severity: sev})
+	}
+	return findings
+}
+
+func TestComputeScoreAddingFindingNeverRaises(t *testing.T) {
+	bases := []struct {
+		name     string
+		findings []schema.Finding
+	}{
+		{"empty", nil},
+		{"one low", findingsOf("low")},
+		{"mixed", findingsOf("critical", "medium", "info")},
+		{"bottomed out", findingsOf("critical", "critical", "critical", "critical", "critical", "critical", "critical")},
+	}
+	weights := []struct {
+		name    string
+		weights map[string]int
+	}{
+		{"default", nil},
+		{"custom", map[string]int{"critical": 40, "info": 1}},
+	}
+	for _, b := range bases {
+		for _, w := range weights {
+			before, _ := ComputeScore(b.findings, w.weights)
+			for _, sev := range append(severities, "", "bogus") {
+				t.Run(b.name+"/"+w.name+"/+"+sev, func(t *testing.T) {
+					after, _ := ComputeScore(append(append([]schema.Finding{}, b.findings...), findingsOf(sev)...), w.weights)
+					if after > before {
+						t.Errorf("adding a %q finding raised the score from %d to %d", sev, before, after)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestComputeScoreSeverityOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings []schema.Finding
+	}{
+		{"alone", nil},
+		{"with others", findingsOf("medium", "low")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// severities runs from most to least severe
+			prev := -1
+			for _, sev := range severities {
+				score, _ := ComputeScore(append(append([]schema.Finding{}, tt.findings...), findingsOf(sev)...), nil)
+				if prev >= 0 && score < prev {
+					t.Errorf("a %s finding scores %d, below the more severe one's %d", sev, score, prev)
+				}
+				prev = score
+			}
+		})
+	}
+}
+
+func TestComputeScoreIgnoresSuppressed(t *testing.T) {
+	active := findingsOf("high", "low")
+	want, wantGrade := ComputeScore(active, nil)
+	for _, sev := range severities {
+		t.Run(sev, func(t *testing.T) {
+			suppressed := findingsOf(sev, sev, sev)
+			for i := range suppressed {
+				suppressed[i].Suppressed = true
+			}
+			got, grade := ComputeScore(append(append([]schema.Finding{}, active...), suppressed...), nil)
+			if got != want || grade != wantGrade {
+				t.Errorf("suppressed %s findings changed the score: got %d %s, want %d %s", sev, got, grade, want, wantGrade)
+			}
+		})
+	}
+}
+
+func TestComputeScoreGrades(t *testing.T) {
+	tests := []struct {
+		name      string
+		findings  []schema.Finding
+		wantScore int
+		wantGrade string
+	}{
+		{"clean", nil, 100, "A"},
+		{"info only", findingsOf("info", "info"), 100, "A"},
+		{"A lower bound", findingsOf("medium", "medium"), 90, "A"},
+		{"B upper bound", findingsOf("medium", "medium", "low"), 88, "B"},
+		{"B lower bound", findingsOf("high", "high"), 80, "B"},
+		{"C upper bound", findingsOf("high", "high", "low"), 78, "C"},
+		{"C lower bound", findingsOf("high", "high", "high"), 70, "C"},
+		{"D upper bound", findingsOf("high", "high", "high", "low"), 68, "D"},
+		{"D lower bound", findingsOf("high", "high", "high", "high"), 60, "D"},
+		{"F upper bound", findingsOf("high", "high", "high", "high", "low"), 58, "F"},
+		{"floor", findingsOf("critical", "critical", "critical", "critical", "critical", "critical", "critical"), 0, "F"},
+		{"cvss fallback", []schema.Finding{{ID: "x", CVSS: 9.8}}, 85, "B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, grade := ComputeScore(tt.findings, nil)
+			if score != tt.wantScore || grade != tt.wantGrade {
+				t.Errorf("got %d %s, want %d %s", score, grade, tt.wantScore, tt.wantGrade)
+			}
+		})
+	}
+}
+
+func TestScoreToGradeBoundaries(t *testing.T) {
+	tests := []struct {
+		score int
+		want  string
+	}{
+		{100, "A"}, {90, "A"}, {89, "B"}, {80, "B"}, {79, "C"}, {70, "C"},
+		{69, "D"}, {60, "D"}, {59, "F"}, {0, "F"},
+	}
+	for _, tt := range tests {
+		if got := scoreToGrade(tt.score); got != tt.want {
+			t.Errorf("scoreToGrade(%d) = %s, want %s", tt.score, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	// markupTag and styleBlock find the report's own markup; escaped text such as
+	// captured responses never contains a raw "<", so it is not inspected
+	markupTag  = regexp.MustCompile(`<[a-zA-Z][^>]*>`)
+	styleBlock = regexp.MustCompile(`(?is)<style\b[^>]*>(.*?)</style>`)
+
+	// tagAsset is an attribute that makes a browser fetch an absolute or
+	// protocol-relative URL; plain <a href> links are not assets and stay allowed
+	tagAsset = regexp.MustCompile(`(?i)(?:\b(?:src|srcset|poster|data|background)\s*=\s*["']?|^<link\b[^>]*\bhref\s*=\s*["']?|url\(\s*["']?)\s*((?:https?:)?//[^\s"'<>)]*)`)
+	// cssAsset is a stylesheet url() or @import of a remote URL
+	cssAsset = regexp.MustCompile(`(?i)(?:url\(|@import)\s*["']?\s*((?:https?:)?//[^\s"'<>)]*)`)
+	// svgHref is an SVG <image>/<use> reference to another document
+	svgHref = regexp.MustCompile(`(?i)\bhref\s*=\s*["']?\s*((?:https?:)?//[^\s"'<>)]*)`)
+)
+
+// checkSelfContained refuses a report that would load anything over the network:
+// it must open the same when emailed or offline, and the PDF must match it
+func checkSelfContained(name string, html []byte) error {
+	return findExternal(name, html, tagAsset)
+}
+
+// checkSVGSelfContained is checkSelfContained for an SVG logo, whose links are
+// fetched when the image is drawn
+func checkSVGSelfContained(name string, svg []byte) error {
+	if err := findExternal(name, svg, tagAsset); err != nil {
+		return err
+	}
+	return findExternal(name, svg, svgHref)
+}
+
+func findExternal(name string, doc []byte, attr *regexp.Regexp) error {
+	for _, tag := range markupTag.FindAll(doc, -1) {
+		if m := attr.FindSubmatch(tag); m != nil {
+			return externalError(name, m[1])
+		}
+	}
+	for _, block := range styleBlock.FindAllSubmatch(doc, -1) {
+		if m := cssAsset.FindSubmatch(block[1]); m != nil {
+			return externalError(name, m[1])
+		}
+	}
+	return nil
+}
+
+func externalError(name string, url []byte) error {
+	return fmt.Errorf("%s would load %s from the network; reports must be self-contained", name, url)
+}
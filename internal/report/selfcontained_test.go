@@ -0,0 +1,126 @@
+package report
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// remoteAsset finds references a browser would fetch over the network when opening
+// the report: tag sources, stylesheet links, CSS url() and @import
+var remoteAsset = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)<[a-z][^>]*\b(?:src|srcset|poster|data|background)\s*=\s*["']?\s*(?:https?:)?//`),
+	regexp.MustCompile(`(?i)<link\b[^>]*\bhref\s*=\s*["']?\s*(?:https?:)?//`),
+	regexp.MustCompile(`(?i)url\(\s*["']?\s*(?:https?:)?//`),
+	regexp.MustCompile(`(?i)@import\s+(?:url\()?\s*["']?\s*(?:https?:)?//`),
+}
+
+func assertNoRemoteAssets(t *testing.T, path string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	for _, re := range remoteAsset {
+		if m := re.Find(data); m != nil {
+			t.Errorf("%s loads a remote asset: %s", path, m)
+		}
+	}
+}
+
+// fixtureResult has links and markup in scanner output that must stay inert text
+func fixtureResult(target string) schema.ScanResult {
+	return schema.ScanResult{
+		Target:    target,
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ScannerMeta: []schema.ScannerMeta{
+			{Name: "nuclei", Version: "3.2.0", Status: schema.ScannerOK},
+		},
+		Findings: []schema.Finding{
+			{
+				ID:          "git-config",
+				Target:      target,
+				Scanner:     "nuclei",
+				Template:    "git-config",
+				Severity:    "high",
+				CVSS:        7.5,
+				CVSSVector:  "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N",
+				Description: `Exposed .git/config, see <img src="https://evil.example/x.png">`,
+				Evidence:    target + "/.git/config",
+				Tags:        []string{"exposure", "CVE-2021-1"},
+				RequestResponse: &schema.HTTPExchange{
+					Request:  "GET /.git/config HTTP/1.1\r\nHost: example.com\r\n\r\n",
+					Response: "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<link rel=\"stylesheet\" href=\"https://cdn.example/a.css\"><script src=\"//cdn.example/a.js\"></script>",
+				},
+			},
+			{
+				ID:       "missing-csp",
+				Target:   target,
+				Scanner:  "headers",
+				Severity: "medium",
+				Evidence: "<style>@import 'https://cdn.example/b.css';</style>",
+			},
+			{
+				ID:       "nginx",
+				Target:   target,
+				Scanner:  "fingerprint",
+				Severity: "info",
+				Tags:     []string{schema.TechnologyTag},
+			},
+		},
+	}
+}
+
+func TestGenerateHTMLSelfContained(t *testing.T) {
+	dir := t.TempDir()
+	path, err := GenerateHTML(fixtureResult("https://example.com"), dir, Options{})
+	if err != nil {
+		t.Fatalf("GenerateHTML: %v", err)
+	}
+	assertNoRemoteAssets(t, path)
+}
+
+func TestGenerateAggregateHTMLSelfContained(t *testing.T) {
+	dir := t.TempDir()
+	results := []schema.ScanResult{fixtureResult("https://example.com"), fixtureResult("https://example.org")}
+	path, err := GenerateAggregateHTML(results, dir, Options{})
+	if err != nil {
+		t.Fatalf("GenerateAggregateHTML: %v", err)
+	}
+	assertNoRemoteAssets(t, path)
+}
+
+func TestCheckSelfContained(t *testing.T) {
+	tests := []struct {
+		name    string
+		html    string
+		wantErr bool
+	}{
+		{"remote img", `<html><body><img src="https://cdn.example/logo.png"></body></html>`, true},
+		{"protocol-relative img", `<img src='//cdn.example/logo.png'>`, true},
+		{"remote script", `<script src="http://cdn.example/app.js"></script>`, true},
+		{"remote stylesheet", `<link rel="stylesheet" href="https://cdn.example/a.css">`, true},
+		{"remote @import", `<style>@import "https://fonts.example/css";</style>`, true},
+		{"remote @import url", `<style>body{color:red} @import url(https://fonts.example/css);</style>`, true},
+		{"remote css url", `<style>body{background:url('https://cdn.example/bg.png')}</style>`, true},
+		{"link to site", `<p>See <a href="https://example.com/advisory">the advisory</a></p>`, false},
+		{"data uri img", `<img src="data:image/png;base64,iVBORw0KGgo=">`, false},
+		{"escaped markup", `<pre>&lt;img src=&#34;https://cdn.example/x.png&#34;&gt; url(https://cdn.example/y)</pre>`, false},
+		{"relative url", `<img src="logo.png"><style>body{background:url(bg.png)}</style>`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSelfContained("report.html", []byte(tt.html))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkSelfContained: got %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "report.html") {
+				t.Errorf("error %q does not name the report", err)
+			}
+		})
+	}
+}
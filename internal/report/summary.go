@@ -0,0 +1,32 @@
+package report
+
+import "github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+
+// Summary is the compact outcome of one scan, for gating in shell pipelines
+type Summary struct {
+	Target    string         `json:"target"`
+	Score     int            `json:"score"`
+	Grade     string         `json:"grade"`
+	RiskScore int            `json:"risk_score"`
+	Counts    map[string]int `json:"counts"`
+	Total     int            `json:"total"`
+}
+
+// Summarize scores res and counts its unsuppressed findings by severity; every
+// severity is present in Counts, zero or not
+func Summarize(res schema.ScanResult) Summary {
+	s := Summary{Target: res.Target, Counts: map[string]int{}}
+	s.Score, s.Grade = ScoreOf(res)
+	s.RiskScore = ComputeRiskScore(res.Findings)
+	for _, sev := range severityOrder {
+		s.Counts[sev] = 0
+	}
+	for _, f := range res.Findings {
+		if f.Suppressed {
+			continue
+		}
+		s.Counts[SeverityOf(f)]++
+		s.Total++
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SeverityStyle is the color and display label used for one severity
+type SeverityStyle struct {
+	Color string `yaml:"color"`
+	Label string `yaml:"label"`
+}
+
+// Theme overrides the severity palette and labels of the HTML report.
+// Keys are lowercase severities (critical, high, medium, low, info).
+type Theme struct {
+	Severities map[string]SeverityStyle `yaml:"severities"`
+}
+
+// DefaultTheme matches the built-in report palette
+func DefaultTheme() Theme {
+	return Theme{Severities: map[string]SeverityStyle{
+		"critical": {Color: "#ff6b6b", Label: "CRITICAL"},
+		"high":     {Color: "#ef4444", Label: "HIGH"},
+		"medium":   {Color: "#f59e0b", Label: "MEDIUM"},
+		"low":      {Color: "#22c55e", Label: "LOW"},
+		"info":     {Color: "#38bdf8", Label: "INFO"},
+	}}
+}
+
+// LoadTheme reads a YAML theme file and merges it over DefaultTheme
+//
+//	severities:
+//	  critical: { color: "#b91c1c", label: "Kritisch" }
+//	  info:     { label: "Hinweis" }
+func LoadTheme(path string) (Theme, error) {
+	theme := DefaultTheme()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return theme, fmt.Errorf("read theme: %w", err)
+	}
+	var override Theme
+	if err := yaml.Unmarshal(data, &override); err != nil {
+		return theme, fmt.Errorf("parse theme: %w", err)
+	}
+	for sev, style := range override.Severities {
+		sev = strings.ToLower(strings.TrimSpace(sev))
+		base, ok := theme.Severities[sev]
+		if !ok {
+			return theme, fmt.Errorf("theme: unknown severity %q", sev)
+		}
+		if style.Color != "" {
+			if !cssColor.MatchString(style.Color) {
+				return theme, fmt.Errorf("theme: invalid color %q for %s", style.Color, sev)
+			}
+			base.Color = style.Color
+		}
+		if style.Label != "" {
+			base.Label = style.Label
+		}
+		theme.Severities[sev] = base
+	}
+	return theme, nil
+}
+
+// cssColor accepts hex colors, rgb()/hsl() functions and named colors
+var cssColor = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|(rgb|rgba|hsl|hsla)\([0-9.,%\s]+\)|[a-zA-Z]+)$`)
+
+// css is the color as a template value. html/template rejects parentheses in CSS, so
+// rgb()/hsl() colors only render when marked safe; anything cssColor does not accept
+// gets the neutral fallback instead.
+func (s SeverityStyle) css() template.CSS {
+	if !cssColor.MatchString(s.Color) {
+		return fallbackColor
+	}
+	return template.CSS(s.Color)
+}
+
+// fallbackColor is used for severities the theme does not know
+const fallbackColor = "#8aa0b5"
+
+func (t Theme) style(sev string) SeverityStyle {
+	if s, ok := t.Severities[sev]; ok {
+		return s
+	}
+	return SeverityStyle{Color: fallbackColor, Label: strings.ToUpper(sev)}
+}
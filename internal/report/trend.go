@@ -0,0 +1,144 @@
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+//go:embed templates/trend.html.tmpl
+var trendHTMLTemplate string
+
+// TrendPoint is the finding count by severity of one historical scan
+type TrendPoint struct {
+	Time   time.Time
+	Dir    string
+	Counts map[string]int // keyed by lowercase severity
+	Total  int
+}
+
+// NewTrendPoint summarizes the scan stored in dir
+func NewTrendPoint(dir string, res schema.ScanResult) TrendPoint {
+	active, _ := splitSuppressed(res.Findings)
+	p := TrendPoint{Time: res.Timestamp, Dir: dir, Counts: map[string]int{}, Total: len(active)}
+	for _, f := range active {
+		p.Counts[SeverityOf(f)]++
+	}
+	return p
+}
+
+// SortTrend orders points from oldest to newest
+func SortTrend(points []TrendPoint) {
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+}
+
+// Chart geometry of the trend page, in SVG user units
+const (
+	trendChartHeight = 220
+	trendBarWidth    = 28
+	trendBarGap      = 14
+)
+
+type trendViewModel struct {
+	Title      string
+	Target     string
+	Severities []severityView
+	Width      int
+	Height     int
+	BarWidth   int
+	Bars       []trendBar
+	Rows       []trendRow
+	Year       int
+}
+
+type trendBar struct {
+	X        int
+	Label    string
+	Total    int
+	Segments []trendSegment
+}
+
+type trendSegment struct {
+	Key    string
+	Y      float64
+	Height float64
+	Count  int
+}
+
+type trendRow struct {
+	Time   string
+	Counts []int
+	Total  int
+	Delta  string
+}
+
+// GenerateTrendHTML renders points as a stacked bar chart with a table and writes it to path
+func GenerateTrendHTML(target string, points []TrendPoint, path string, opts Options) (string, error) {
+	theme := opts.theme()
+	vm := trendViewModel{
+		Title:    fallback(strings.TrimSpace(opts.Title), "Findings Trend — "+target),
+		Target:   target,
+		Width:    max(len(points), 1)*(trendBarWidth+trendBarGap) + trendBarGap,
+		Height:   trendChartHeight,
+		BarWidth: trendBarWidth,
+		Year:     time.Now().UTC().Year(),
+	}
+	for _, sev := range severityOrder {
+		style := theme.style(sev)
+		vm.Severities = append(vm.Severities, severityView{Key: strings.ToUpper(sev), Label: style.Label, Color: style.css()})
+	}
+
+	peak := 1
+	for _, p := range points {
+		peak = max(peak, p.Total)
+	}
+	for i, p := range points {
+		bar := trendBar{
+			X:     trendBarGap + i*(trendBarWidth+trendBarGap),
+			Label: p.Time.UTC().Format("01-02"),
+			Total: p.Total,
+		}
+		y := float64(trendChartHeight)
+		row := trendRow{Time: p.Time.UTC().Format(time.RFC3339), Total: p.Total}
+		// Stack the most severe findings at the bottom
+		for _, sev := range severityOrder {
+			n := p.Counts[sev]
+			row.Counts = append(row.Counts, n)
+			if n == 0 {
+				continue
+			}
+			h := float64(n) * trendChartHeight / float64(peak)
+			y -= h
+			bar.Segments = append(bar.Segments, trendSegment{Key: strings.ToUpper(sev), Y: y, Height: h, Count: n})
+		}
+		if i > 0 {
+			row.Delta = fmt.Sprintf("%+d", p.Total-points[i-1].Total)
+		}
+		vm.Bars = append(vm.Bars, bar)
+		vm.Rows = append(vm.Rows, row)
+	}
+
+	tmpl, err := template.New("trend").Parse(trendHTMLTemplate)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vm); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("create out dir: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	return path, nil
+}
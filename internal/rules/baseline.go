@@ -0,0 +1,25 @@
+package rules
+
+import "github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+
+// baselineKey identifies "the same finding" across scans; it is computed rather
+// than read from ID so baselines saved before stable IDs still match
+func baselineKey(f schema.Finding) string {
+	return schema.StableID(f)
+}
+
+// NewFindings returns the active findings of current that are absent from baseline,
+// matching on Template+Target+Evidence
+func NewFindings(current, baseline []schema.Finding) []schema.Finding {
+	known := make(map[string]bool, len(baseline))
+	for _, f := range baseline {
+		known[baselineKey(f)] = true
+	}
+	var out []schema.Finding
+	for _, f := range current {
+		if !f.Suppressed && !known[baselineKey(f)] {
+			out = append(out, f)
+		}
+	}
+	return out
+}
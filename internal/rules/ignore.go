@@ -0,0 +1,127 @@
+// Package rules holds post-scan rules applied to findings before reporting
+package rules
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+// DefaultIgnoreFile is picked up from the working directory when no --ignore-file is given
+const DefaultIgnoreFile = ".yoroignore"
+
+// IgnoreRule suppresses findings by template ID glob or CVE, optionally for one target only
+type IgnoreRule struct {
+	// Target limits the rule to one normalized target; empty matches every target
+	Target string
+	// Pattern is a CVE ID or a glob over template IDs, e.g. "http-missing-*"
+	Pattern string
+	// Line is the rule's line in the ignore file, for SuppressedBy
+	Line int
+}
+
+// IgnoreList is a parsed ignore file
+type IgnoreList struct {
+	Path  string
+	Rules []IgnoreRule
+}
+
+var cveID = regexp.MustCompile(`(?i)^CVE-\d{4}-\d{4,}$`)
+
+// LoadIgnoreFile parses an ignore file. Each non-comment line is either
+//
+//	<template-glob|CVE>            e.g. tech-detect, http-missing-*, CVE-2021-44228
+//	<target> <template-glob|CVE>   e.g. https://example.com ssl-*
+func LoadIgnoreFile(file string) (*IgnoreList, error) {
+	fh, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ignore file: %w", err)
+	}
+	defer fh.Close()
+
+	list := &IgnoreList{Path: file}
+	sc := bufio.NewScanner(fh)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		var r IgnoreRule
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			r = IgnoreRule{Pattern: fields[0], Line: n}
+		case 2:
+			target, err := utils.NormalizeTarget(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", file, n, err)
+			}
+			r = IgnoreRule{Target: target, Pattern: fields[1], Line: n}
+		default:
+			return nil, fmt.Errorf("%s:%d: expected \"<pattern>\" or \"<target> <pattern>\"", file, n)
+		}
+		if _, err := path.Match(r.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid pattern %q: %w", file, n, r.Pattern, err)
+		}
+		list.Rules = append(list.Rules, r)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+	return list, nil
+}
+
+// Match reports whether the rule covers f
+func (r IgnoreRule) Match(f schema.Finding) bool {
+	if r.Target != "" && r.Target != f.Target {
+		return false
+	}
+	if cveID.MatchString(r.Pattern) {
+		if strings.EqualFold(f.ID, r.Pattern) || strings.EqualFold(f.Template, r.Pattern) {
+			return true
+		}
+		for _, t := range f.Tags {
+			if strings.EqualFold(t, r.Pattern) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, id := range []string{f.Template, f.ID} {
+		if ok, _ := path.Match(r.Pattern, id); ok && id != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply marks matching findings as suppressed and returns how many it marked.
+// Findings are kept so reports can list them as accepted risks.
+func (l *IgnoreList) Apply(findings []schema.Finding) int {
+	if l == nil {
+		return 0
+	}
+	n := 0
+	for i := range findings {
+		if findings[i].Suppressed {
+			continue
+		}
+		for _, r := range l.Rules {
+			if r.Match(findings[i]) {
+				findings[i].Suppressed = true
+				findings[i].SuppressedBy = fmt.Sprintf("%s:%d", l.Path, r.Line)
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
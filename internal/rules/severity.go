@@ -0,0 +1,115 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// originalSeverityTag prefixes the tag that records a finding's severity before an override
+const originalSeverityTag = "original-severity:"
+
+// SeverityRule reclassifies findings whose template/ID matches Template (a glob) or
+// that carry Tag; exactly one of the two is set
+type SeverityRule struct {
+	Template string `yaml:"template"`
+	Tag      string `yaml:"tag"`
+	Severity string `yaml:"severity"`
+}
+
+// SeverityMap is a parsed --severity-map file; the first matching rule wins
+type SeverityMap struct {
+	Path  string
+	Rules []SeverityRule `yaml:"overrides"`
+}
+
+// LoadSeverityMap parses a severity map file
+//
+//	overrides:
+//	  - template: missing-header-strict-transport-security
+//	    severity: low
+//	  - tag: tech
+//	    severity: info
+func LoadSeverityMap(file string) (*SeverityMap, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read severity map: %w", err)
+	}
+	m := &SeverityMap{}
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse severity map %s: %w", file, err)
+	}
+	m.Path = file
+	for i, r := range m.Rules {
+		r.Severity = strings.ToLower(strings.TrimSpace(r.Severity))
+		if schema.SeverityRank(r.Severity) < 0 {
+			return nil, fmt.Errorf("%s: rule %d: invalid severity %q (expected critical, high, medium, low or info)", file, i+1, r.Severity)
+		}
+		if (r.Template == "") == (r.Tag == "") {
+			return nil, fmt.Errorf("%s: rule %d: set exactly one of template or tag", file, i+1)
+		}
+		if _, err := path.Match(r.Template, ""); err != nil {
+			return nil, fmt.Errorf("%s: rule %d: invalid template pattern %q: %w", file, i+1, r.Template, err)
+		}
+		m.Rules[i] = r
+	}
+	return m, nil
+}
+
+// Match reports whether the rule covers f
+func (r SeverityRule) Match(f schema.Finding) bool {
+	if r.Tag != "" {
+		for _, t := range f.Tags {
+			if strings.EqualFold(t, r.Tag) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, id := range []string{f.Template, f.ID} {
+		if ok, _ := path.Match(r.Template, id); ok && id != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply overrides the severity of matching findings, tagging each with its original
+// severity, and returns how many it changed. Applying the same map again is a no-op.
+func (m *SeverityMap) Apply(findings []schema.Finding) int {
+	if m == nil {
+		return 0
+	}
+	n := 0
+	for i := range findings {
+		f := &findings[i]
+		for _, r := range m.Rules {
+			if !r.Match(*f) {
+				continue
+			}
+			if !strings.EqualFold(f.Severity, r.Severity) {
+				if !hasTagPrefix(f.Tags, originalSeverityTag) {
+					f.Tags = append(f.Tags, originalSeverityTag+strings.ToLower(f.Severity))
+				}
+				f.Severity = r.Severity
+				n++
+			}
+			break
+		}
+	}
+	return n
+}
+
+func hasTagPrefix(tags []string, prefix string) bool {
+	for _, t := range tags {
+		if strings.HasPrefix(t, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package scanners
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// CertExpiryWarning is how close to expiry a certificate gets a high finding
+const CertExpiryWarning = 30 * 24 * time.Hour
+
+func init() {
+	Register(Info{
+		Name:        "cert",
+		Description: "Built-in TLS certificate check: expiry, self-signed and hostname mismatch",
+
+		DefaultTimeout: time.Minute,
+	}, func(Options) Scanner { return certScanner{} })
+}
+
+// certScanner ignores TLSOptions: it has to accept any certificate to report on
+// it, so it always checks the certificate by hand
+type certScanner struct{}
+
+func (certScanner) Name() string { return "cert" }
+
+func (certScanner) Run(ctx context.Context, target string) ([]schema.Finding, error) {
+	return runCertCheck(ctx, target)
+}
+
+// RunCertCheck connects to target over TLS and reports problems with its certificate
+func RunCertCheck(target string) ([]schema.Finding, error) {
+	return runCertCheck(context.Background(), target)
+}
+
+func runCertCheck(ctx context.Context, target string) ([]schema.Finding, error) {
+	host, addr := certAddress(target)
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	// Verification is done by hand so a bad certificate becomes findings instead of a dial error
+	d := tls.Dialer{Config: &tls.Config{ServerName: host, InsecureSkipVerify: true}}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s over TLS: %w", addr, err)
+	}
+	defer conn.Close()
+
+	chain := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("%s presented no certificate", addr)
+	}
+	return certFindings(target, host, addr, chain, time.Now()), nil
+}
+
+// certAddress returns the TLS server name and host:port for target; the port is 443
+// unless an https target names its own
+func certAddress(target string) (host, addr string) {
+	port := "443"
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		host = u.Hostname()
+		if u.Port() != "" && u.Scheme == "https" {
+			port = u.Port()
+		}
+	} else {
+		host = hostOf(target)
+	}
+	return host, net.JoinHostPort(host, port)
+}
+
+// certFindings checks the leaf certificate of chain as seen at now
+func certFindings(target, host, addr string, chain []*x509.Certificate, now time.Time) []schema.Finding {
+	leaf := chain[0]
+	subject := fallbackStr(leaf.Subject.CommonName, leaf.Subject.String())
+	evidence := fmt.Sprintf("%s: %s, valid %s to %s", addr, subject,
+		leaf.NotBefore.UTC().Format(time.DateOnly), leaf.NotAfter.UTC().Format(time.DateOnly))
+
+	var findings []schema.Finding
+	add := func(id, sev, desc, rec string) {
+		findings = append(findings, schema.Finding{
+			ID:             id,
+			Target:         target,
+			Scanner:        "cert",
+			Template:       id,
+			Severity:       sev,
+			Description:    desc,
+			Evidence:       evidence,
+			Recommendation: rec,
+			Tags:           []string{"tls", "certificate"},
+		})
+	}
+
+	left := leaf.NotAfter.Sub(now)
+	switch {
+	case left <= 0:
+		add("cert-expired", "critical",
+			fmt.Sprintf("The TLS certificate expired on %s", leaf.NotAfter.UTC().Format(time.DateOnly)),
+			"Renew the certificate now and automate renewal (e.g. with ACME/Let's Encrypt)")
+	case left <= CertExpiryWarning:
+		add("cert-expiring-soon", "high",
+			fmt.Sprintf("The TLS certificate expires in %d days (%s)", int(left.Hours()/24), leaf.NotAfter.UTC().Format(time.DateOnly)),
+			"Renew the certificate before it expires and automate renewal (e.g. with ACME/Let's Encrypt)")
+	}
+	if now.Before(leaf.NotBefore) {
+		add("cert-not-yet-valid", "high",
+			fmt.Sprintf("The TLS certificate is not valid until %s", leaf.NotBefore.UTC().Format(time.DateOnly)),
+			"Check the server clock and the certificate's validity period")
+	}
+	if isSelfSigned(leaf) {
+		add("cert-self-signed", "medium",
+			"The TLS certificate is self-signed, so browsers and clients will not trust it",
+			"Use a certificate issued by a trusted certificate authority")
+	}
+	if err := leaf.VerifyHostname(host); err != nil {
+		add("cert-hostname-mismatch", "high",
+			fmt.Sprintf("The TLS certificate is not valid for %s (covers %s)", host, certNames(leaf)),
+			"Issue a certificate whose subject alternative names include this hostname")
+	}
+	return findings
+}
+
+func isSelfSigned(c *x509.Certificate) bool {
+	return c.Issuer.String() == c.Subject.String() && c.CheckSignatureFrom(c) == nil
+}
+
+// certNames lists the names a certificate is valid for
+func certNames(c *x509.Certificate) string {
+	names := append([]string{}, c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		names = append(names, ip.String())
+	}
+	if len(names) == 0 {
+		names = append(names, c.Subject.CommonName)
+	}
+	return strings.Join(names, ", ")
+}
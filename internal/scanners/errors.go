@@ -0,0 +1,69 @@
+package scanners
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// stderrTailSize bounds how much of a tool's stderr a ScannerError keeps
+const stderrTailSize = 4 << 10
+
+// ScannerError is returned when an external scanner fails to run or exits with an error
+type ScannerError struct {
+	Scanner string
+	// ExitCode is the tool's exit status, or -1 when it did not start or was killed
+	ExitCode int
+	// Stderr is the tail of the tool's stderr output
+	Stderr string
+	Err    error
+}
+
+func (e *ScannerError) Error() string { return fmt.Sprintf("%s failed: %v", e.Scanner, e.Err) }
+func (e *ScannerError) Unwrap() error { return e.Err }
+
+// newScannerError builds a ScannerError for a failed run, taking the stderr captured in tail
+func newScannerError(scanner string, err error, tail *tailBuffer) *ScannerError {
+	se := &ScannerError{Scanner: scanner, ExitCode: -1, Err: err}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		se.ExitCode = exitErr.ExitCode()
+	}
+	if tail != nil {
+		se.Stderr = strings.TrimSpace(tail.String())
+	}
+	return se
+}
+
+// captureStderr attaches st to cmd and also keeps the tail of cmd's stderr
+func (st streams) captureStderr(cmd *exec.Cmd) *tailBuffer {
+	tail := &tailBuffer{}
+	st.attach(cmd)
+	cmd.Stderr = io.MultiWriter(st.stderr, tail)
+	return tail
+}
+
+// tailBuffer is an io.Writer that keeps only the last stderrTailSize bytes written
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - stderrTailSize; over > 0 {
+		t.buf = t.buf[over:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
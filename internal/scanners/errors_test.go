@@ -0,0 +1,100 @@
+package scanners
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// runFailing runs script under sh the way scanners run their tools
+func runFailing(t *testing.T, script string) (*ScannerError, *bytes.Buffer) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	var stdout, stderr bytes.Buffer
+	st := streams{stdout: &stdout, stderr: &stderr}
+	cmd := exec.Command("sh", "-c", script)
+	tail := st.captureStderr(cmd)
+	err := cmd.Run()
+	if err == nil {
+		t.Fatalf("%q succeeded, want a failure", script)
+	}
+	return newScannerError("fake", err, tail), &stderr
+}
+
+func TestScannerErrorFromFailedCommand(t *testing.T) {
+	se, stderr := runFailing(t, "echo starting; echo boom >&2; exit 3")
+	if se.ExitCode != 3 {
+		t.Errorf("ExitCode = %d, want 3", se.ExitCode)
+	}
+	if se.Stderr != "boom" {
+		t.Errorf("Stderr = %q, want %q", se.Stderr, "boom")
+	}
+	if se.Scanner != "fake" || !strings.HasPrefix(se.Error(), "fake failed: ") {
+		t.Errorf("got scanner %q error %q", se.Scanner, se.Error())
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(se, &exitErr) {
+		t.Errorf("ScannerError does not unwrap to *exec.ExitError: %v", se.Err)
+	}
+	// The tail is a copy: the tool's stderr still reaches the stream
+	if got := strings.TrimSpace(stderr.String()); got != "boom" {
+		t.Errorf("stream stderr = %q, want %q", got, "boom")
+	}
+}
+
+func TestScannerErrorStderrCapped(t *testing.T) {
+	// 200 numbered lines of 100 bytes each, far more than the tail keeps
+	script := `i=0; while [ $i -lt 200 ]; do printf '%099d\n' $i >&2; i=$((i+1)); done; echo last line >&2; exit 1`
+	se, stderr := runFailing(t, script)
+	if se.ExitCode != 1 {
+		t.Errorf("ExitCode = %d, want 1", se.ExitCode)
+	}
+	if len(se.Stderr) > stderrTailSize {
+		t.Errorf("Stderr is %d bytes, want at most %d", len(se.Stderr), stderrTailSize)
+	}
+	if !strings.HasSuffix(se.Stderr, "last line") {
+		t.Errorf("Stderr does not end with the last output: ...%q", se.Stderr[max(0, len(se.Stderr)-40):])
+	}
+	if strings.Contains(se.Stderr, strings.Repeat("0", 99)) {
+		t.Error("Stderr kept the first line, want only the tail")
+	}
+	if stderr.Len() <= stderrTailSize {
+		t.Errorf("stream got %d bytes, want the full output", stderr.Len())
+	}
+}
+
+func TestScannerErrorNotStarted(t *testing.T) {
+	err := exec.Command("yoro-no-such-tool").Run()
+	se := newScannerError("fake", err, nil)
+	if se.ExitCode != -1 {
+		t.Errorf("ExitCode = %d, want -1", se.ExitCode)
+	}
+	if se.Stderr != "" {
+		t.Errorf("Stderr = %q, want empty", se.Stderr)
+	}
+	if !errors.Is(se, exec.ErrNotFound) {
+		t.Errorf("want exec.ErrNotFound, got %v", se.Err)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	tail := &tailBuffer{}
+	chunk := strings.Repeat("a", 1000)
+	for i := 0; i < 10; i++ {
+		if n, err := tail.Write([]byte(chunk)); n != len(chunk) || err != nil {
+			t.Fatalf("Write = %d, %v", n, err)
+		}
+	}
+	_, _ = tail.Write([]byte("end"))
+	got := tail.String()
+	if len(got) != stderrTailSize {
+		t.Errorf("kept %d bytes, want %d", len(got), stderrTailSize)
+	}
+	if !strings.HasSuffix(got, "end") {
+		t.Errorf("tail does not end with the last write: %q", got[len(got)-10:])
+	}
+}
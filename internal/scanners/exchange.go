@@ -0,0 +1,63 @@
+package scanners
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// maxExchangeText is how much of a captured request or response is kept
+const maxExchangeText = 4 << 10
+
+// sensitiveHeader matches header names whose values are credentials or session state
+var sensitiveHeader = regexp.MustCompile(`(?i)^(?:authorization|proxy-authorization|cookie|set-cookie|.*(?:token|secret|api-?key|session|password).*)$`)
+
+// newHTTPExchange builds the evidence of a web finding from a raw request and
+// response, masking sensitive headers; nil when neither was captured
+func newHTTPExchange(request, response string) *schema.HTTPExchange {
+	if strings.TrimSpace(request) == "" && strings.TrimSpace(response) == "" {
+		return nil
+	}
+	ex := &schema.HTTPExchange{}
+	var cut bool
+	ex.Request, cut = truncateText(redactHTTPHeaders(request), maxExchangeText)
+	ex.Truncated = cut
+	ex.Response, cut = truncateText(redactHTTPHeaders(response), maxExchangeText)
+	ex.Truncated = ex.Truncated || cut
+	return ex
+}
+
+// exchangeOf captures what fetchPage sent and received for the final request
+func exchangeOf(resp *http.Response, body []byte) *schema.HTTPExchange {
+	req, _ := httputil.DumpRequest(resp.Request, false)
+	head, _ := httputil.DumpResponse(resp, false)
+	return newHTTPExchange(string(req), string(head)+string(body))
+}
+
+// redactHTTPHeaders masks the values of sensitive headers in raw HTTP text; the
+// start line and the body are kept as they are
+func redactHTTPHeaders(raw string) string {
+	lines := strings.SplitAfter(raw, "\n")
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r\n")
+		if line == "" {
+			break
+		}
+		name, _, ok := strings.Cut(line, ":")
+		if ok && sensitiveHeader.MatchString(strings.TrimSpace(name)) {
+			lines[i] = name + ": [REDACTED]" + lines[i][len(line):]
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// truncateText cuts s to at most n bytes without splitting a UTF-8 character
+func truncateText(s string, n int) (string, bool) {
+	if len(s) <= n {
+		return s, false
+	}
+	return strings.ToValidUTF8(s[:n], ""), true
+}
@@ -0,0 +1,144 @@
+package scanners
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+func init() {
+	Register(Info{
+		Name:        "fingerprint",
+		Description: "Built-in technology detection (web server, framework, CMS, JS libraries) from headers and page content",
+
+		DefaultTimeout: time.Minute,
+	}, func(o Options) Scanner {
+		return fingerprintScanner{proxy: o.Nuclei.Proxy, headers: o.Nuclei.Headers, tls: o.TLS}
+	})
+}
+
+// fingerprintScanner fetches the page the way the headers scanner does
+type fingerprintScanner struct {
+	proxy   string
+	headers []string
+	tls     TLSOptions
+}
+
+func (fingerprintScanner) Name() string { return "fingerprint" }
+
+func (s fingerprintScanner) Run(ctx context.Context, target string) ([]schema.Finding, error) {
+	resp, body, err := fetchPage(ctx, target, s.proxy, s.headers, s.tls)
+	if err != nil {
+		return nil, err
+	}
+	return techFindings(target, detectTechnologies(resp.Header, body), exchangeOf(resp, body)), nil
+}
+
+// techSignature recognizes one technology in a response header, or in the body
+// when Header is empty; the first group of Pattern, if any, captures the version
+type techSignature struct {
+	Name     string
+	Category string
+	Header   string
+	Pattern  *regexp.Regexp
+}
+
+// techSignatures are checked in order; a technology matched by several keeps the
+// first version found
+var techSignatures = []techSignature{
+	{"nginx", "web-server", "Server", regexp.MustCompile(`(?i)\bnginx(?:/([\d.]+))?`)},
+	{"Apache HTTP Server", "web-server", "Server", regexp.MustCompile(`(?i)\bapache(?:/([\d.]+))?`)},
+	{"Microsoft IIS", "web-server", "Server", regexp.MustCompile(`(?i)\bmicrosoft-iis(?:/([\d.]+))?`)},
+	{"LiteSpeed", "web-server", "Server", regexp.MustCompile(`(?i)\blitespeed\b`)},
+	{"Caddy", "web-server", "Server", regexp.MustCompile(`(?i)^caddy\b`)},
+	{"Cloudflare", "cdn", "Server", regexp.MustCompile(`(?i)^cloudflare\b`)},
+	{"Varnish", "cache", "Via", regexp.MustCompile(`(?i)\bvarnish\b`)},
+	{"PHP", "language", "X-Powered-By", regexp.MustCompile(`(?i)\bphp(?:/([\d.]+))?`)},
+	{"ASP.NET", "framework", "X-AspNet-Version", regexp.MustCompile(`([\d.]+)`)},
+	{"ASP.NET", "framework", "X-Powered-By", regexp.MustCompile(`(?i)\basp\.net\b`)},
+	{"Express", "framework", "X-Powered-By", regexp.MustCompile(`(?i)^express\b`)},
+	{"Next.js", "framework", "X-Powered-By", regexp.MustCompile(`(?i)\bnext\.js(?: ([\d.]+))?`)},
+	{"Drupal", "cms", "X-Generator", regexp.MustCompile(`(?i)\bdrupal(?: (\d+))?`)},
+	{"WordPress", "cms", "", regexp.MustCompile(`(?i)<meta[^>]+name=["']generator["'][^>]+content=["']WordPress ?([\d.]+)?`)},
+	{"WordPress", "cms", "", regexp.MustCompile(`/wp-(?:content|includes)/`)},
+	{"Drupal", "cms", "", regexp.MustCompile(`(?i)<meta[^>]+name=["']generator["'][^>]+content=["']Drupal ?(\d+)?`)},
+	{"Joomla", "cms", "", regexp.MustCompile(`(?i)<meta[^>]+name=["']generator["'][^>]+content=["']Joomla!? ?([\d.]+)?`)},
+	{"Shopify", "ecommerce", "", regexp.MustCompile(`cdn\.shopify\.com`)},
+	{"jQuery", "js-library", "", regexp.MustCompile(`(?i)jquery[.-]?(\d+\.\d+(?:\.\d+)?)?(?:\.min)?\.js`)},
+	{"React", "js-library", "", regexp.MustCompile(`data-reactroot|react(?:-dom)?(?:\.production)?\.min\.js`)},
+}
+
+// technology is one detected technology and what gave it away
+type technology struct {
+	Name     string
+	Category string
+	Version  string
+	Evidence string
+}
+
+// detectTechnologies matches techSignatures against a response, one entry per technology
+func detectTechnologies(header http.Header, body []byte) []technology {
+	var found []technology
+	index := map[string]int{}
+	for _, sig := range techSignatures {
+		var m []string
+		var evidence string
+		if sig.Header != "" {
+			v := header.Get(sig.Header)
+			if m = sig.Pattern.FindStringSubmatch(v); m != nil {
+				evidence = sig.Header + ": " + v
+			}
+		} else if m = sig.Pattern.FindStringSubmatch(string(body)); m != nil {
+			evidence = "page contains " + truncateEvidence(m[0])
+		}
+		if m == nil {
+			continue
+		}
+		version := ""
+		if len(m) > 1 {
+			version = m[1]
+		}
+		if i, ok := index[sig.Name]; ok {
+			if found[i].Version == "" && version != "" {
+				found[i].Version, found[i].Evidence = version, evidence
+			}
+			continue
+		}
+		index[sig.Name] = len(found)
+		found = append(found, technology{Name: sig.Name, Category: sig.Category, Version: version, Evidence: evidence})
+	}
+	return found
+}
+
+func truncateEvidence(s string) string {
+	if len(s) > 120 {
+		return s[:120] + "…"
+	}
+	return s
+}
+
+func techFindings(target string, techs []technology, ex *schema.HTTPExchange) []schema.Finding {
+	findings := make([]schema.Finding, 0, len(techs))
+	for _, t := range techs {
+		label := strings.TrimSpace(t.Name + " " + t.Version)
+		findings = append(findings, schema.Finding{
+			ID:             "tech-" + strings.ToLower(strings.NewReplacer(" ", "-", ".", "").Replace(t.Name)),
+			Target:         target,
+			Scanner:        "fingerprint",
+			Template:       "tech-fingerprint",
+			Severity:       "info",
+			Description:    "Detected: " + label,
+			Evidence:       t.Evidence,
+			Recommendation: fmt.Sprintf("Keep %s up to date and avoid advertising its version where possible", t.Name),
+			Tags:           []string{schema.TechnologyTag, "category:" + t.Category},
+
+			RequestResponse: ex,
+		})
+	}
+	return findings
+}
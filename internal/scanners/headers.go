@@ -0,0 +1,159 @@
+package scanners
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+func init() {
+	Register(Info{
+		Name:        "headers",
+		Description: "Built-in check for missing HTTP security headers (HSTS, CSP, X-Frame-Options, ...)",
+
+		DefaultTimeout: time.Minute,
+	}, func(o Options) Scanner {
+		return headersScanner{proxy: o.Nuclei.Proxy, headers: o.Nuclei.Headers, tls: o.TLS}
+	})
+}
+
+// headersScanner shares --proxy and --header with nuclei so authenticated pages are checked too
+type headersScanner struct {
+	proxy   string
+	headers []string
+	tls     TLSOptions
+}
+
+func (headersScanner) Name() string { return "headers" }
+
+func (s headersScanner) Run(ctx context.Context, target string) ([]schema.Finding, error) {
+	return runHeaderCheck(ctx, target, s.proxy, s.headers, s.tls)
+}
+
+// RunHeaderCheck fetches target and reports each missing security header
+func RunHeaderCheck(target string) ([]schema.Finding, error) {
+	return runHeaderCheck(context.Background(), target, "", nil, TLSOptions{})
+}
+
+// securityHeader is one response header the headers scanner expects
+type securityHeader struct {
+	Name        string
+	Severity    string
+	HTTPSOnly   bool
+	Description string
+	Remedy      string
+}
+
+var securityHeaders = []securityHeader{
+	{"Strict-Transport-Security", "medium", true,
+		"Browsers are not told to always use HTTPS, so a first visit can be downgraded or intercepted",
+		"Send Strict-Transport-Security: max-age=31536000; includeSubDomains once the whole site works over HTTPS"},
+	{"Content-Security-Policy", "medium", false,
+		"No Content Security Policy limits where scripts may load from, so injected scripts run unhindered",
+		"Define a Content-Security-Policy (start with Content-Security-Policy-Report-Only) that avoids 'unsafe-inline' and wildcards"},
+	{"X-Frame-Options", "medium", false,
+		"Pages can be framed by other sites, enabling clickjacking",
+		"Send X-Frame-Options: DENY (or SAMEORIGIN), or a CSP frame-ancestors directive"},
+	{"X-Content-Type-Options", "low", false,
+		"Browsers may MIME-sniff responses and execute them as a different content type",
+		"Send X-Content-Type-Options: nosniff"},
+	{"Referrer-Policy", "low", false,
+		"Full URLs, which may contain tokens or personal data, can leak to other sites via the Referer header",
+		"Send Referrer-Policy: strict-origin-when-cross-origin (or stricter)"},
+}
+
+func runHeaderCheck(ctx context.Context, target, proxy string, extra []string, tlsOpts TLSOptions) ([]schema.Finding, error) {
+	resp, body, err := fetchPage(ctx, target, proxy, extra, tlsOpts)
+	if err != nil {
+		return nil, err
+	}
+	return headerFindings(target, resp, exchangeOf(resp, body)), nil
+}
+
+// fetchPage GETs target like a browser would (following redirects, with the
+// --header values and proxy) and returns the final response with up to 1 MiB of
+// its body; the body is already closed
+func fetchPage(ctx context.Context, target, proxy string, extra []string, tlsOpts TLSOptions) (*http.Response, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build request for %s: %w", target, err)
+	}
+	req.Header.Set("User-Agent", "yorosec-agent")
+	for _, h := range extra {
+		if name, value, ok := strings.Cut(h, ":"); ok {
+			req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+	}
+
+	tlsConfig, err := tlsOpts.Config()
+	if err != nil {
+		return nil, nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if proxy != "" {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid proxy URL %q: %w", proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		var unknown x509.UnknownAuthorityError
+		if errors.As(err, &unknown) {
+			return nil, nil, fmt.Errorf("failed to fetch %s: %w (trust its CA with --ca-file, or skip verification with --insecure-skip-verify)", target, err)
+		}
+		return nil, nil, fmt.Errorf("failed to fetch %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+	// A body cut off mid-transfer still comes with headers worth checking
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return resp, body, nil
+}
+
+// headerFindings checks the final response (after redirects) for missing security
+// headers; ex, the captured exchange, is attached to each finding
+func headerFindings(target string, resp *http.Response, ex *schema.HTTPExchange) []schema.Finding {
+	final := resp.Request.URL
+	evidence := fmt.Sprintf("GET %s → %s", final, resp.Status)
+	var findings []schema.Finding
+	for _, h := range securityHeaders {
+		if h.HTTPSOnly && final.Scheme != "https" {
+			continue
+		}
+		if resp.Header.Get(h.Name) != "" {
+			continue
+		}
+		// A CSP frame-ancestors directive supersedes X-Frame-Options
+		if h.Name == "X-Frame-Options" && strings.Contains(strings.ToLower(resp.Header.Get("Content-Security-Policy")), "frame-ancestors") {
+			continue
+		}
+		id := "missing-header-" + strings.ToLower(h.Name)
+		findings = append(findings, schema.Finding{
+			ID:             id,
+			Target:         target,
+			Scanner:        "headers",
+			Template:       id,
+			Severity:       h.Severity,
+			Description:    fmt.Sprintf("Missing %s header: %s", h.Name, h.Description),
+			Evidence:       evidence,
+			Recommendation: h.Remedy,
+			Tags:           []string{"headers", "misconfig"},
+
+			RequestResponse: ex,
+		})
+	}
+	return findings
+}
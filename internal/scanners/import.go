@@ -0,0 +1,62 @@
+package scanners
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// importer parses a report file written by a scanner run outside yoro
+type importer struct {
+	parse func(data []byte, target string) ([]schema.Finding, error)
+	// perHost parsers can file findings under their own hosts when no target is given
+	perHost bool
+}
+
+var importers = map[string]importer{
+	"nuclei": {parse: func(data []byte, target string) ([]schema.Finding, error) {
+		return parseNucleiJSON(data, target, NucleiOptions{})
+	}},
+	"nmap": {parse: parseNmapXML, perHost: true},
+	"openvas": {parse: func(data []byte, target string) ([]schema.Finding, error) {
+		return parseOpenVASXML(bytes.NewReader(data), target)
+	}, perHost: true},
+	"zap":     {parse: parseZAPJSON},
+	"nikto":   {parse: parseNiktoJSON},
+	"trivy":   {parse: parseTrivyJSON},
+	"masscan": {parse: parseMasscanJSON},
+}
+
+// ImportFormats lists the scanners whose reports Import understands
+func ImportFormats() []string {
+	names := make([]string, 0, len(importers))
+	for name := range importers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ImportNeedsTarget reports whether reports of scanner carry no host of their own,
+// so the caller has to name the target
+func ImportNeedsTarget(scanner string) bool {
+	return !importers[scanner].perHost
+}
+
+// Import parses the report at path, as written by scanner (e.g. nuclei -json-export
+// or nmap -oX), with the same logic as a scan run
+func Import(scanner, path, target string) ([]schema.Finding, error) {
+	imp, ok := importers[scanner]
+	if !ok {
+		return nil, fmt.Errorf("cannot import %q reports (supported: %s)", scanner, strings.Join(ImportFormats(), ", "))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s report: %w", scanner, err)
+	}
+	return imp.parse(data, target)
+}
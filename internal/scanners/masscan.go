@@ -0,0 +1,200 @@
+package scanners
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// DefaultMasscanPorts is scanned when --ports is not given
+const DefaultMasscanPorts = "1-1024"
+
+// MasscanOptions tunes how masscan is invoked
+type MasscanOptions struct {
+	// Ports is a masscan port list, e.g. "80,443,8000-8100"
+	Ports string
+}
+
+func init() {
+	Register(Info{
+		Name:        "masscan",
+		Description: "Fast TCP port discovery for large ranges (requires root)",
+		Binary:      "masscan",
+
+		DefaultTimeout: 30 * time.Minute,
+	}, func(o Options) Scanner { return masscanScanner{ports: o.Masscan.Ports, st: o.streams()} })
+}
+
+type masscanScanner struct {
+	ports string
+	st    streams
+}
+
+func (masscanScanner) Name() string { return "masscan" }
+
+func (s masscanScanner) Run(ctx context.Context, target string) ([]schema.Finding, error) {
+	return runMasscan(ctx, target, s.ports, s.st)
+}
+
+func (s masscanScanner) CommandLine(target string) string {
+	return MasscanCommandLine(target, s.ports)
+}
+
+// RunMasscan scans the ports of target's host with masscan and reports each open port
+func RunMasscan(target string, ports string) ([]schema.Finding, error) {
+	return runMasscan(context.Background(), target, ports, defaultStreams)
+}
+
+func runMasscan(ctx context.Context, target, ports string, st streams) ([]schema.Finding, error) {
+	ip, err := masscanAddress(target)
+	if err != nil {
+		return nil, err
+	}
+	tmpFile := filepath.Join(os.TempDir(), fmt.Sprintf("masscan_%d.json", time.Now().UnixNano()))
+	defer os.Remove(tmpFile)
+
+	// The stderr tail also tells permission failures apart
+	cmd := exec.CommandContext(ctx, "masscan", masscanArgs(ip, ports, tmpFile)...)
+	tail := st.captureStderr(cmd)
+
+	if err := cmd.Run(); err != nil {
+		se := newScannerError("masscan", err, tail)
+		msg := strings.ToLower(se.Stderr)
+		if strings.Contains(msg, "permission denied") || strings.Contains(msg, "operation not permitted") || strings.Contains(msg, "run as root") {
+			se.Err = fmt.Errorf("needs raw socket access: run yoro as root or grant it with `setcap cap_net_raw,cap_net_admin+eip $(which masscan)`: %w", err)
+		}
+		return nil, se
+	}
+
+	data, err := os.ReadFile(tmpFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read masscan output: %w", err)
+	}
+	if err := st.keepRaw("masscan", data); err != nil {
+		return nil, err
+	}
+	return parseMasscanJSON(data, target)
+}
+
+func masscanArgs(ip, ports, outFile string) []string {
+	if ports == "" {
+		ports = DefaultMasscanPorts
+	}
+	return []string{ip, "-p", ports, "-oJ", outFile}
+}
+
+// MasscanCommandLine renders the masscan invocation for display
+func MasscanCommandLine(target, ports string) string {
+	host := hostOf(target)
+	if ip := net.ParseIP(host); ip == nil {
+		host = "<" + host + " resolved>"
+	}
+	return "masscan " + strings.Join(masscanArgs(host, ports, "<tmp>.json"), " ")
+}
+
+// masscanAddress resolves target's host to an IPv4 address; masscan takes no hostnames
+func masscanAddress(target string) (string, error) {
+	host := hostOf(target)
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+	addrs, err := net.LookupHost(host)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s for masscan: %w", host, err)
+	}
+	for _, a := range addrs {
+		if ip := net.ParseIP(a); ip != nil && ip.To4() != nil {
+			return a, nil
+		}
+	}
+	return "", fmt.Errorf("masscan needs an IPv4 address but %s resolves to %s", host, strings.Join(addrs, ", "))
+}
+
+// hostOf returns the bare host of a URL or host[:port] target
+func hostOf(target string) string {
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	if h, _, err := net.SplitHostPort(target); err == nil {
+		return h
+	}
+	return target
+}
+
+type masscanHost struct {
+	IP    string `json:"ip"`
+	Ports []struct {
+		Port   int    `json:"port"`
+		Proto  string `json:"proto"`
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+		TTL    int    `json:"ttl"`
+	} `json:"ports"`
+}
+
+func parseMasscanJSON(data []byte, target string) ([]schema.Finding, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	// Older masscan versions leave a trailing comma before the closing bracket
+	if i := bytes.LastIndexByte(data, ']'); i > 0 {
+		head := bytes.TrimRight(data[:i], " \t\r\n")
+		data = append(bytes.TrimSuffix(head, []byte(",")), ']')
+	}
+
+	var hosts []masscanHost
+	if err := json.Unmarshal(data, &hosts); err != nil {
+		return nil, fmt.Errorf("failed to parse masscan JSON: %w", err)
+	}
+
+	var findings []schema.Finding
+	seen := map[string]bool{}
+	for _, h := range hosts {
+		for _, p := range h.Ports {
+			if p.Status != "" && p.Status != "open" {
+				continue
+			}
+			proto := fallbackStr(p.Proto, "tcp")
+			key := fmt.Sprintf("%s:%d/%s", h.IP, p.Port, proto)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			findings = append(findings, schema.Finding{
+				ID:             fmt.Sprintf("open-port-%d-%s", p.Port, proto),
+				Target:         target,
+				Scanner:        "masscan",
+				Template:       "masscan-open-port",
+				Severity:       "info",
+				Description:    fmt.Sprintf("Open port %d/%s", p.Port, proto),
+				Evidence:       fmt.Sprintf("%s (%s, ttl %d)", net.JoinHostPort(h.IP, strconv.Itoa(p.Port)), fallbackStr(p.Reason, "open"), p.TTL),
+				Recommendation: "Confirm the service on this port is meant to be reachable; close or firewall it otherwise",
+				Tags:           []string{"port", proto},
+			})
+		}
+	}
+	return findings, nil
+}
+
+func fallbackStr(s, fb string) string {
+	if s == "" {
+		return fb
+	}
+	return s
+}
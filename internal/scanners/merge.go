@@ -0,0 +1,130 @@
+package scanners
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+var cvePattern = regexp.MustCompile(`(?i)^CVE-\d{4}-\d{4,}$`)
+
+// MergeFindings collapses findings that different scanners reported for the same
+// CVE on the same target into one, keeping the highest severity. Merged findings
+// list every reporter in Scanner ("nuclei,trivy") and as "source:<name>" tags.
+// Repeated hits from a single scanner are distinct occurrences and are kept.
+func MergeFindings(findings []schema.Finding) []schema.Finding {
+	out := make([]schema.Finding, 0, len(findings))
+	byKey := map[string]int{}
+
+	for _, f := range findings {
+		cve := findingCVE(f)
+		if cve == "" {
+			out = append(out, f)
+			continue
+		}
+		key := f.Target + "|" + cve
+		i, seen := byKey[key]
+		if !seen || hasSource(out[i], f.Scanner) {
+			byKey[key] = len(out)
+			out = append(out, f)
+			continue
+		}
+		out[i] = mergeInto(out[i], f)
+	}
+	return out
+}
+
+// AssignStableIDs replaces IDs that only name the template (nuclei, zap, nikto,
+// OpenVAS) with schema.StableID, so each occurrence can be told apart across scans.
+// IDs a scanner made unique itself (e.g. open-port-443-tcp) are kept.
+func AssignStableIDs(findings []schema.Finding) {
+	for i, f := range findings {
+		if f.ID == "" || f.ID == f.Template {
+			findings[i].ID = schema.StableID(f)
+		}
+	}
+}
+
+// findingCVE returns the CVE a finding refers to, from its ID, template or tags
+func findingCVE(f schema.Finding) string {
+	for _, c := range append([]string{f.ID, f.Template}, f.Tags...) {
+		if cvePattern.MatchString(c) {
+			return strings.ToUpper(c)
+		}
+	}
+	return ""
+}
+
+func sources(f schema.Finding) []string {
+	return strings.Split(f.Scanner, ",")
+}
+
+func hasSource(f schema.Finding, scanner string) bool {
+	for _, s := range sources(f) {
+		if s == scanner {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeInto(dst, src schema.Finding) schema.Finding {
+	if schema.SeverityRank(src.Severity) > schema.SeverityRank(dst.Severity) {
+		dst.Severity = src.Severity
+	}
+	if src.CVSS > dst.CVSS {
+		dst.CVSS = src.CVSS
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	if dst.Evidence == "" {
+		dst.Evidence = src.Evidence
+	} else if src.Evidence != "" && src.Evidence != dst.Evidence {
+		dst.Evidence += "; " + src.Evidence
+	}
+	if dst.Recommendation == "" {
+		dst.Recommendation = src.Recommendation
+	}
+
+	srcs := append(sources(dst), src.Scanner)
+	sort.Strings(srcs)
+	dst.Scanner = strings.Join(srcs, ",")
+
+	tags := map[string]bool{}
+	var merged []string
+	add := func(t string) {
+		if !tags[t] {
+			tags[t] = true
+			merged = append(merged, t)
+		}
+	}
+	for _, t := range append(dst.Tags, src.Tags...) {
+		if !strings.HasPrefix(t, "source:") {
+			add(t)
+		}
+	}
+	for _, s := range srcs {
+		add("source:" + s)
+	}
+	dst.Tags = merged
+	return dst
+}
+
+// CapFindings keeps at most max findings (no limit when max <= 0), preferring the most
+// severe and unsuppressed ones, and reports whether any were dropped
+func CapFindings(findings []schema.Finding, max int) ([]schema.Finding, bool) {
+	if max <= 0 || len(findings) <= max {
+		return findings, false
+	}
+	kept := append([]schema.Finding(nil), findings...)
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].Suppressed != kept[j].Suppressed {
+			return !kept[i].Suppressed
+		}
+		return schema.SeverityRank(kept[i].Severity) > schema.SeverityRank(kept[j].Severity)
+	})
+	return kept[:max], true
+}
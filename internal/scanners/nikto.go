@@ -0,0 +1,139 @@
+package scanners
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+func init() {
+	Register(Info{
+		Name:        "nikto",
+		Description: "Nikto web server misconfiguration and dangerous file scanner",
+		Binary:      "nikto",
+
+		DefaultTimeout: time.Hour,
+	}, func(o Options) Scanner { return niktoScanner{st: o.streams()} })
+}
+
+type niktoScanner struct{ st streams }
+
+func (niktoScanner) Name() string { return "nikto" }
+
+func (s niktoScanner) Run(ctx context.Context, target string) ([]schema.Finding, error) {
+	return runNikto(ctx, target, s.st)
+}
+
+func (niktoScanner) CommandLine(target string) string { return NiktoCommandLine(target) }
+
+// RunNikto executes nikto with a JSON report and returns normalized findings
+func RunNikto(target string) ([]schema.Finding, error) {
+	return runNikto(context.Background(), target, defaultStreams)
+}
+
+func runNikto(ctx context.Context, target string, st streams) ([]schema.Finding, error) {
+	base := filepath.Join(os.TempDir(), fmt.Sprintf("nikto_%d", time.Now().UnixNano()))
+	tmpFile, logFile := base+".json", base+".log"
+	defer os.Remove(tmpFile)
+
+	// nikto prints every check it runs, so its console output goes to a log file
+	logFh, err := os.Create(logFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nikto log: %w", err)
+	}
+	defer logFh.Close()
+
+	cmd := exec.CommandContext(ctx, "nikto", niktoArgs(target, tmpFile)...)
+	tail := st.captureStderr(cmd)
+	cmd.Stdout = logFh
+
+	// nikto can exit non-zero after writing a complete report, so trust the report if present
+	runErr := cmd.Run()
+	data, err := os.ReadFile(tmpFile)
+	if err != nil {
+		if runErr != nil {
+			return nil, newScannerError("nikto", fmt.Errorf("%w (log: %s)", runErr, logFile), tail)
+		}
+		return nil, fmt.Errorf("failed to read nikto output: %w", err)
+	}
+	// The console log is only worth keeping when nikto failed
+	os.Remove(logFile)
+	if err := st.keepRaw("nikto", data); err != nil {
+		return nil, err
+	}
+	return parseNiktoJSON(data, target)
+}
+
+func niktoArgs(target, reportFile string) []string {
+	return []string{"-h", target, "-Format", "json", "-output", reportFile, "-ask", "no", "-nointeractive"}
+}
+
+// NiktoCommandLine renders the nikto invocation for display
+func NiktoCommandLine(target string) string {
+	return "nikto " + strings.Join(niktoArgs(target, "<tmp>.json"), " ") + " > <tmp>.log"
+}
+
+type niktoHost struct {
+	Host            string `json:"host"`
+	Port            string `json:"port"`
+	Vulnerabilities []struct {
+		ID         string `json:"id"`
+		OSVDB      string `json:"OSVDB"`
+		Method     string `json:"method"`
+		URL        string `json:"url"`
+		Msg        string `json:"msg"`
+		References string `json:"references"`
+	} `json:"vulnerabilities"`
+}
+
+// niktoMedium marks items that point at exploitable issues rather than hardening advice
+var niktoMedium = regexp.MustCompile(`(?i)(vulnerab|injection|xss|cross-site|traversal|remote|backdoor|default (account|credential|password)|directory indexing|admin(istration)? (login|page|interface)|\.bak|backup|config(uration)? file|phpinfo|shell)`)
+
+func parseNiktoJSON(data []byte, target string) ([]schema.Finding, error) {
+	// nikto 2.5 writes an array of hosts, 2.1 a single host object
+	var hosts []niktoHost
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		var h niktoHost
+		if err := json.Unmarshal(data, &h); err != nil {
+			return nil, fmt.Errorf("failed to parse nikto JSON: %w", err)
+		}
+		hosts = []niktoHost{h}
+	} else if err := json.Unmarshal(data, &hosts); err != nil {
+		return nil, fmt.Errorf("failed to parse nikto JSON: %w", err)
+	}
+
+	var findings []schema.Finding
+	for _, h := range hosts {
+		for _, v := range h.Vulnerabilities {
+			sev := "low"
+			if niktoMedium.MatchString(v.Msg) {
+				sev = "medium"
+			}
+			f := schema.Finding{
+				ID:          "nikto-" + v.ID,
+				Target:      target,
+				Scanner:     "nikto",
+				Template:    "nikto-" + v.ID,
+				Severity:    sev,
+				Description: strings.TrimSpace(v.Msg),
+				Evidence:    strings.TrimSpace(v.Method + " " + v.URL),
+			}
+			if v.OSVDB != "" && v.OSVDB != "0" {
+				f.Tags = append(f.Tags, "OSVDB-"+v.OSVDB)
+			}
+			f.Tags = append(f.Tags, strings.Fields(v.References)...)
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
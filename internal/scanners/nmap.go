@@ -0,0 +1,85 @@
+package scanners
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+type nmapRun struct {
+	Hosts []struct {
+		Addresses []struct {
+			Addr string `xml:"addr,attr"`
+			Type string `xml:"addrtype,attr"`
+		} `xml:"address"`
+		Hostnames []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"hostnames>hostname"`
+		Ports []struct {
+			Protocol string `xml:"protocol,attr"`
+			PortID   string `xml:"portid,attr"`
+			State    struct {
+				State  string `xml:"state,attr"`
+				Reason string `xml:"reason,attr"`
+			} `xml:"state"`
+			Service struct {
+				Name    string `xml:"name,attr"`
+				Product string `xml:"product,attr"`
+				Version string `xml:"version,attr"`
+			} `xml:"service"`
+		} `xml:"ports>port"`
+	} `xml:"host"`
+}
+
+// parseNmapXML reports each open port of an nmap -oX report, like masscan does.
+// With an empty target each finding's Target is the scanned host.
+func parseNmapXML(data []byte, target string) ([]schema.Finding, error) {
+	var run nmapRun
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse nmap XML: %w", err)
+	}
+
+	var findings []schema.Finding
+	for _, h := range run.Hosts {
+		var ip string
+		for _, a := range h.Addresses {
+			if a.Type == "ipv4" || a.Type == "ipv6" {
+				ip = a.Addr
+				break
+			}
+		}
+		host := ip
+		if len(h.Hostnames) > 0 && h.Hostnames[0].Name != "" {
+			host = h.Hostnames[0].Name
+		}
+		for _, p := range h.Ports {
+			if p.State.State != "open" {
+				continue
+			}
+			proto := fallbackStr(p.Protocol, "tcp")
+			desc := fmt.Sprintf("Open port %s/%s", p.PortID, proto)
+			if svc := strings.TrimSpace(strings.Join([]string{p.Service.Name, p.Service.Product, p.Service.Version}, " ")); svc != "" {
+				desc += " (" + strings.Join(strings.Fields(svc), " ") + ")"
+			}
+			f := schema.Finding{
+				ID:             fmt.Sprintf("open-port-%s-%s", p.PortID, proto),
+				Target:         fallbackStr(target, host),
+				Scanner:        "nmap",
+				Template:       "nmap-open-port",
+				Severity:       "info",
+				Description:    desc,
+				Evidence:       fmt.Sprintf("%s (%s)", net.JoinHostPort(fallbackStr(ip, host), p.PortID), fallbackStr(p.State.Reason, "open")),
+				Recommendation: "Confirm the service on this port is meant to be reachable; close or firewall it otherwise",
+				Tags:           []string{"port", proto},
+			}
+			if p.Service.Name != "" {
+				f.Tags = append(f.Tags, p.Service.Name)
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
@@ -1,31 +1,132 @@
 package scanners
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
 )
 
+// NucleiOptions tunes how nuclei is invoked
+type NucleiOptions struct {
+	// Proxy is an http(s):// or socks5:// URL forwarded to nuclei's -proxy
+	Proxy string
+	// Headers are raw "Name: value" pairs forwarded to nuclei's -header
+	Headers []string
+	// IncludeTags, ExcludeTags and ExcludeIDs forward to -include-tags, -exclude-tags and -exclude-id
+	IncludeTags []string
+	ExcludeTags []string
+	ExcludeIDs  []string
+	// Severities limits the templates run to these severities (-severity)
+	Severities []string
+	// Workflow is a nuclei workflow file forwarded to -w; the workflow decides which
+	// templates run, so it may override the default template selection
+	Workflow string
+	// IncludeRaw keeps each original nuclei record in Finding.Raw
+	IncludeRaw bool
+	// URLs are per-target endpoints (--url-list); a target listed here is scanned
+	// with -list of its URLs instead of -target, and findings keep their URL as Target
+	URLs map[string][]string
+	// MaxFindings stops parsing after one record more than this (0 for no limit),
+	// enough for the caller to tell the export was truncated
+	MaxFindings int
+
+	// streamStats makes nuclei emit JSONL results and stats for progress reporting
+	streamStats bool
+}
+
+func init() {
+	Register(Info{
+		Name:        "nuclei",
+		Description: "Template-based web/network vulnerability scanner",
+		Binary:      "nuclei",
+
+		StreamsProgress: true,
+		DefaultTimeout:  time.Hour,
+	}, func(o Options) Scanner {
+		s := nucleiScanner{opts: o.Nuclei, st: o.streams()}
+		if o.Progress != nil {
+			pw := newProgressWriter("nuclei", o.Progress)
+			s.opts.streamStats = true
+			s.st.stdout, s.st.stderr = pw, pw
+		}
+		return s
+	})
+}
+
+type nucleiScanner struct {
+	opts NucleiOptions
+	st   streams
+}
+
+func (nucleiScanner) Name() string { return "nuclei" }
+
+func (s nucleiScanner) Run(ctx context.Context, target string) ([]schema.Finding, error) {
+	return runNuclei(ctx, target, s.opts, s.st)
+}
+
+func (s nucleiScanner) CommandLine(target string) string {
+	return NucleiCommandLine(target, s.opts)
+}
+
+func (nucleiScanner) Version(ctx context.Context) (string, error) {
+	return probeVersion(ctx, "nuclei", "-version")
+}
+
 // RunNuclei executes nuclei with JSON export and returns normalized findings
-func RunNuclei(target string) ([]schema.Finding, error) {
+func RunNuclei(target string, opts NucleiOptions) ([]schema.Finding, error) {
+	return runNuclei(context.Background(), target, opts, defaultStreams)
+}
+
+func runNuclei(ctx context.Context, target string, opts NucleiOptions, st streams) ([]schema.Finding, error) {
 	// Prepare temp output file
 	tmpFile := filepath.Join(os.TempDir(), fmt.Sprintf("nuclei_%d.json", time.Now().UnixNano()))
+	defer os.Remove(tmpFile)
 
-	cmd := exec.Command("nuclei",
-		"-target", target,
-		"-json-export", tmpFile,
-	)
+	input := []string{"-target", target}
+	urls := opts.URLs[target]
+	if len(urls) > 0 {
+		listFile := filepath.Join(os.TempDir(), fmt.Sprintf("nuclei_%d.list", time.Now().UnixNano()))
+		if err := os.WriteFile(listFile, []byte(strings.Join(urls, "\n")+"\n"), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write nuclei URL list: %w", err)
+		}
+		defer os.Remove(listFile)
+		input = []string{"-list", listFile}
+	}
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd := exec.CommandContext(ctx, "nuclei", nucleiArgs(input, tmpFile, opts)...)
+	tail := st.captureStderr(cmd)
+	// Interrupt instead of killing on cancellation, so nuclei shuts down cleanly and
+	// writes its export; WaitDelay still kills it if it does not stop in time
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
 
 	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("nuclei failed: %w", err)
+		serr := newScannerError("nuclei", err, tail)
+		if ctx.Err() == nil {
+			return nil, serr
+		}
+		// Stopped by a deadline: keep whatever nuclei exported before it exited
+		data, _ := os.ReadFile(tmpFile)
+		if len(data) == 0 {
+			return nil, serr
+		}
+		if err := st.keepRaw("nuclei", data); err != nil {
+			return nil, errors.Join(serr, err)
+		}
+		findings, _ := parseNucleiJSON(data, target, opts)
+		return findings, serr
 	}
 
 	// Read back JSON
@@ -33,34 +134,302 @@ func RunNuclei(target string) ([]schema.Finding, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read nuclei output: %w", err)
 	}
+	if err := st.keepRaw("nuclei", data); err != nil {
+		return nil, err
+	}
+
+	findings, err := parseNucleiJSON(data, target, opts)
+	if err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
 
-	// Nuclei exports an array of objects
-	var raw []map[string]interface{}
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return nil, fmt.Errorf("failed to parse nuclei JSON: %w", err)
+// nucleiRecord is the part of a nuclei result we map; field types accept the
+// variants seen across nuclei versions (e.g. "cve-id" as a string or a list)
+type nucleiRecord struct {
+	TemplateID string `json:"template-id"`
+	Info       *struct {
+		Severity       string     `json:"severity"`
+		Description    string     `json:"description"`
+		Tags           stringList `json:"tags"`
+		Classification *struct {
+			CVEID       stringList `json:"cve-id"`
+			CWEID       stringList `json:"cwe-id"`
+			CVSSScore   looseFloat `json:"cvss-score"`
+			CVSSMetrics string     `json:"cvss-metrics"`
+		} `json:"classification"`
+	} `json:"info"`
+	URL       string `json:"url"`
+	MatchedAt string `json:"matched-at"`
+	// Request and Response are the raw HTTP exchange (absent with -omit-raw)
+	Request  string `json:"request"`
+	Response string `json:"response"`
+}
+
+// parseNucleiJSON normalizes nuclei findings from a -json-export array or -jsonl
+// output. It does no I/O; records that are not result objects are skipped. When
+// the data is cut short, the findings decoded before the error come back with it.
+func parseNucleiJSON(data []byte, target string, opts NucleiOptions) ([]schema.Finding, error) {
+	// Decode the records one at a time so a cap stops early
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("failed to parse nuclei JSON: %w", err)
+		}
 	}
+	urls := opts.URLs[target]
 
 	var findings []schema.Finding
-	for _, r := range raw {
-		f := schema.Finding{
-			Target:  target,
-			Scanner: "nuclei",
-		}
-		if id, ok := r["template-id"].(string); ok {
-			f.ID = id
-			f.Template = id
+	for dec.More() {
+		if opts.MaxFindings > 0 && len(findings) > opts.MaxFindings {
+			break
 		}
-		if sev, ok := r["info"].(map[string]interface{})["severity"].(string); ok {
-			f.Severity = sev
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return findings, fmt.Errorf("failed to parse nuclei JSON: %w", err)
 		}
-		if desc, ok := r["info"].(map[string]interface{})["description"].(string); ok {
-			f.Description = desc
+		var rec nucleiRecord
+		if err := json.Unmarshal(raw, &rec); err != nil || rec.TemplateID == "" {
+			continue
 		}
-		if matched, ok := r["matched-at"].(string); ok {
-			f.Evidence = matched
+		f := nucleiFinding(rec, target, len(urls) > 0)
+		if opts.IncludeRaw {
+			f.Raw = raw
 		}
 		findings = append(findings, f)
 	}
 
 	return findings, nil
 }
+
+// nucleiFinding maps one record; with perURL each endpoint is its own target
+func nucleiFinding(rec nucleiRecord, target string, perURL bool) schema.Finding {
+	f := schema.Finding{
+		ID:       rec.TemplateID,
+		Target:   target,
+		Scanner:  "nuclei",
+		Template: rec.TemplateID,
+		Evidence: rec.MatchedAt,
+
+		RequestResponse: newHTTPExchange(rec.Request, rec.Response),
+	}
+	if perURL {
+		// Fall back to the match when nuclei omits the input URL
+		f.Target = fallbackStr(rec.URL, fallbackStr(rec.MatchedAt, target))
+	}
+	if rec.Info == nil {
+		return f
+	}
+	f.Severity = schema.NormalizeSeverity(rec.Info.Severity)
+	f.Description = rec.Info.Description
+	f.Tags = append(f.Tags, rec.Info.Tags...)
+	if class := rec.Info.Classification; class != nil {
+		for _, c := range class.CVEID {
+			f.Tags = append(f.Tags, strings.ToUpper(c))
+		}
+		for _, c := range class.CWEID {
+			f.Tags = append(f.Tags, "cwe:"+strings.TrimPrefix(strings.ToLower(c), "cwe-"))
+		}
+		f.CVSS = float64(class.CVSSScore)
+		f.CVSSVector = strings.TrimSpace(class.CVSSMetrics)
+	}
+	return f
+}
+
+// stringList decodes a JSON list of strings, a single comma-separated string or null
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*l = nil
+		for _, s := range strings.Split(one, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				*l = append(*l, s)
+			}
+		}
+		return nil
+	}
+	var many []json.RawMessage
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*l = nil
+	for _, m := range many {
+		var s string
+		if json.Unmarshal(m, &s) == nil && strings.TrimSpace(s) != "" {
+			*l = append(*l, strings.TrimSpace(s))
+		}
+	}
+	return nil
+}
+
+// looseFloat decodes a number or a numeric string; anything else is 0
+type looseFloat float64
+
+func (f *looseFloat) UnmarshalJSON(data []byte) error {
+	var n float64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*f = looseFloat(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		n, _ = strconv.ParseFloat(strings.TrimSpace(s), 64)
+	}
+	*f = looseFloat(n)
+	return nil
+}
+
+// nucleiArgs builds the nuclei command line (without the binary name); input is
+// "-target <target>" or "-list <file>"
+func nucleiArgs(input []string, exportFile string, opts NucleiOptions) []string {
+	args := append(slices.Clone(input), "-json-export", exportFile)
+	if opts.Proxy != "" {
+		args = append(args, "-proxy", opts.Proxy)
+	}
+	for _, h := range opts.Headers {
+		args = append(args, "-header", h)
+	}
+	args = append(args, nucleiSelectionArgs(opts)...)
+	if opts.streamStats {
+		args = append(args, "-jsonl", "-stats", "-stats-json", "-stats-interval", "2")
+	}
+	return args
+}
+
+// nucleiSelectionArgs are the flags that choose which templates run
+func nucleiSelectionArgs(opts NucleiOptions) []string {
+	var args []string
+	if len(opts.IncludeTags) > 0 {
+		args = append(args, "-include-tags", strings.Join(opts.IncludeTags, ","))
+	}
+	if len(opts.ExcludeTags) > 0 {
+		args = append(args, "-exclude-tags", strings.Join(opts.ExcludeTags, ","))
+	}
+	if len(opts.ExcludeIDs) > 0 {
+		args = append(args, "-exclude-id", strings.Join(opts.ExcludeIDs, ","))
+	}
+	if len(opts.Severities) > 0 {
+		args = append(args, "-severity", strings.Join(opts.Severities, ","))
+	}
+	if opts.Workflow != "" {
+		args = append(args, "-w", opts.Workflow)
+	}
+	return args
+}
+
+// NucleiCommandLine renders the nuclei invocation for display, with header
+// values and proxy credentials redacted so secrets never reach the terminal
+func NucleiCommandLine(target string, opts NucleiOptions) string {
+	input := []string{"-target", target}
+	if len(opts.URLs[target]) > 0 {
+		input = []string{"-list", "<tmp>.list"}
+	}
+	args := nucleiArgs(input, "<tmp>.json", opts)
+	for i := 1; i < len(args); i++ {
+		switch args[i-1] {
+		case "-header":
+			args[i] = redactHeader(args[i])
+		case "-proxy":
+			if u, err := url.Parse(args[i]); err == nil {
+				args[i] = u.Redacted()
+			}
+		}
+	}
+	for i, a := range args {
+		if strings.ContainsAny(a, " \t\"'") {
+			args[i] = fmt.Sprintf("%q", a)
+		}
+	}
+	return "nuclei " + strings.Join(args, " ")
+}
+
+var templateToken = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateWorkflow checks that a --nuclei-workflow file exists and is readable
+func ValidateWorkflow(path string) error {
+	fh, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("invalid --nuclei-workflow: %w", err)
+	}
+	defer fh.Close()
+	info, err := fh.Stat()
+	if err != nil {
+		return fmt.Errorf("invalid --nuclei-workflow: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("invalid --nuclei-workflow: %s is a directory, not a workflow file", path)
+	}
+	return nil
+}
+
+// ValidateTemplateFilters checks tag and template ID filters and rejects a tag that is
+// both included and excluded
+func ValidateTemplateFilters(opts NucleiOptions) error {
+	for _, list := range []struct {
+		flag string
+		vals []string
+	}{
+		{"--include-tags", opts.IncludeTags},
+		{"--exclude-tags", opts.ExcludeTags},
+		{"--exclude-template-id", opts.ExcludeIDs},
+	} {
+		for _, v := range list.vals {
+			if !templateToken.MatchString(v) {
+				return fmt.Errorf("invalid %s value %q (letters, digits, '.', '_' and '-' only)", list.flag, v)
+			}
+		}
+	}
+	for _, sev := range opts.Severities {
+		if schema.SeverityRank(sev) < 0 {
+			return fmt.Errorf("invalid --nuclei-severity %q (expected critical, high, medium, low or info)", sev)
+		}
+	}
+	excluded := map[string]bool{}
+	for _, t := range opts.ExcludeTags {
+		excluded[strings.ToLower(t)] = true
+	}
+	for _, t := range opts.IncludeTags {
+		if excluded[strings.ToLower(t)] {
+			return fmt.Errorf("tag %q is both in --include-tags and --exclude-tags", t)
+		}
+	}
+	return nil
+}
+
+// ValidateHeader checks that a header is in "Name: value" form
+func ValidateHeader(h string) error {
+	name, _, ok := strings.Cut(h, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("invalid header %q: expected \"Name: value\"", h)
+	}
+	return nil
+}
+
+func redactHeader(h string) string {
+	name, _, ok := strings.Cut(h, ":")
+	if !ok {
+		return "[REDACTED]"
+	}
+	return strings.TrimSpace(name) + ": [REDACTED]"
+}
+
+// ValidateProxy checks that a proxy URL is well-formed and uses a scheme nuclei understands
+func ValidateProxy(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL %q: %w", proxy, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", proxy)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("invalid proxy URL %q: missing host", proxy)
+	}
+	return nil
+}
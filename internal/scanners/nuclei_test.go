@@ -0,0 +1,229 @@
+package scanners
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+func TestParseNucleiJSONSeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		severity string
+		want     string
+	}{
+		{"lowercase", `"high"`, "high"},
+		{"uppercase", `"CRITICAL"`, "critical"},
+		{"padded", `" low "`, "low"},
+		{"synonym", `"moderate"`, "medium"},
+		{"level", `"4"`, "critical"},
+		{"cvss score", `"7.5"`, "high"},
+		{"unknown", `"bogus"`, "info"},
+		{"empty", `""`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := fmt.Sprintf(`{"template-id":"t1","info":{"severity":%s}}`, tt.severity)
+			findings, err := parseNucleiJSON([]byte(data), "https://example.com", NucleiOptions{})
+			if err != nil {
+				t.Fatalf("parseNucleiJSON: %v", err)
+			}
+			if len(findings) != 1 {
+				t.Fatalf("got %d findings, want 1", len(findings))
+			}
+			if got := findings[0].Severity; got != tt.want {
+				t.Errorf("severity %s: got %q, want %q", tt.severity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNucleiJSONMissingInfo(t *testing.T) {
+	data := `{"template-id":"no-info","matched-at":"https://example.com/x"}`
+	findings, err := parseNucleiJSON([]byte(data), "https://example.com", NucleiOptions{})
+	if err != nil {
+		t.Fatalf("parseNucleiJSON: %v", err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1", len(findings))
+	}
+	f := findings[0]
+	if f.ID != "no-info" || f.Template != "no-info" || f.Scanner != "nuclei" {
+		t.Errorf("got id %q template %q scanner %q", f.ID, f.Template, f.Scanner)
+	}
+	if f.Target != "https://example.com" || f.Evidence != "https://example.com/x" {
+		t.Errorf("got target %q evidence %q", f.Target, f.Evidence)
+	}
+	if f.Severity != "" || f.Description != "" || len(f.Tags) != 0 || f.CVSS != 0 {
+		t.Errorf("want no info fields, got severity %q description %q tags %v cvss %v", f.Severity, f.Description, f.Tags, f.CVSS)
+	}
+}
+
+func TestParseNucleiJSONClassification(t *testing.T) {
+	tests := []struct {
+		name           string
+		classification string
+		want           []string
+	}{
+		{"arrays", `{"cve-id":["CVE-2021-1","cve-2021-2"],"cwe-id":["CWE-79","cwe-89"]}`, []string{"CVE-2021-1", "CVE-2021-2", "cwe:79", "cwe:89"}},
+		{"strings", `{"cve-id":"cve-2021-1","cwe-id":"CWE-79"}`, []string{"CVE-2021-1", "cwe:79"}},
+		{"comma separated", `{"cve-id":"CVE-2021-1, CVE-2021-2","cwe-id":"cwe-79,cwe-89"}`, []string{"CVE-2021-1", "CVE-2021-2", "cwe:79", "cwe:89"}},
+		{"nulls", `{"cve-id":null,"cwe-id":null}`, nil},
+		{"blank entries", `{"cve-id":["", " "],"cwe-id":""}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := fmt.Sprintf(`{"template-id":"t1","info":{"severity":"high","tags":"xss","classification":%s}}`, tt.classification)
+			findings, err := parseNucleiJSON([]byte(data), "https://example.com", NucleiOptions{})
+			if err != nil {
+				t.Fatalf("parseNucleiJSON: %v", err)
+			}
+			if len(findings) != 1 {
+				t.Fatalf("got %d findings, want 1", len(findings))
+			}
+			want := append([]string{"xss"}, tt.want...)
+			if got := findings[0].Tags; !slices.Equal(got, want) {
+				t.Errorf("tags: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseNucleiJSONCVSS(t *testing.T) {
+	data := `{"template-id":"t1","info":{"severity":"high","classification":{"cvss-score":"9.8","cvss-metrics":" CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H "}}}`
+	findings, err := parseNucleiJSON([]byte(data), "https://example.com", NucleiOptions{})
+	if err != nil {
+		t.Fatalf("parseNucleiJSON: %v", err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1", len(findings))
+	}
+	if f := findings[0]; f.CVSS != 9.8 || f.CVSSVector != "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H" {
+		t.Errorf("got cvss %v vector %q", f.CVSS, f.CVSSVector)
+	}
+}
+
+func TestParseNucleiJSONSkipsNonResults(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"number", `1` + "\n" + `{"template-id":"a"}`, []string{"a"}},
+		{"string", `"hello"` + "\n" + `{"template-id":"a"}`, []string{"a"}},
+		{"nested array", `[{"template-id":"a"},[1,2],null,{"template-id":"b"}]`, []string{"a", "b"}},
+		{"no template id", `{"info":{"severity":"high"}}` + "\n" + `{"template-id":"a"}`, []string{"a"}},
+		{"wrong field type", `{"template-id":42}` + "\n" + `{"template-id":"a"}`, []string{"a"}},
+		{"stats line", `{"duration":"0:00:02","percent":"50"}` + "\n" + `{"template-id":"a"}`, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := parseNucleiJSON([]byte(tt.data), "https://example.com", NucleiOptions{})
+			if err != nil {
+				t.Fatalf("parseNucleiJSON: %v", err)
+			}
+			if got := findingIDs(findings); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNucleiJSONMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string // findings decoded before the error
+	}{
+		{"truncated object", `{"template-id":"a"`, nil},
+		{"truncated array", `[{"template-id":"a"},`, []string{"a"}},
+		{"garbage line", `{"template-id":"a"}` + "\n" + `not json`, []string{"a"}},
+		{"cut mid-record", `{"template-id":"a"}` + "\n" + `{"template-id":"b"}` + "\n" + `{"template-id":"c","inf`, []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := parseNucleiJSON([]byte(tt.data), "https://example.com", NucleiOptions{})
+			if err == nil {
+				t.Error("want an error, got nil")
+			}
+			if got := findingIDs(findings); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNucleiJSONInputForms(t *testing.T) {
+	records := []string{
+		`{"template-id":"a","info":{"severity":"high"}}`,
+		`{"template-id":"b","info":{"severity":"low"}}`,
+	}
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"json array", "[" + strings.Join(records, ",") + "]", []string{"a", "b"}},
+		{"indented array", "\n  [\n" + strings.Join(records, ",\n") + "\n]\n", []string{"a", "b"}},
+		{"jsonl", strings.Join(records, "\n") + "\n", []string{"a", "b"}},
+		{"jsonl with blank lines", "\n" + records[0] + "\n\n" + records[1], []string{"a", "b"}},
+		{"empty array", "[]", nil},
+		{"empty file", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := parseNucleiJSON([]byte(tt.data), "https://example.com", NucleiOptions{})
+			if err != nil {
+				t.Fatalf("parseNucleiJSON: %v", err)
+			}
+			if got := findingIDs(findings); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNucleiJSONMaxFindings(t *testing.T) {
+	var lines []string
+	for i := 0; i < 5; i++ {
+		lines = append(lines, fmt.Sprintf(`{"template-id":"t%d"}`, i))
+	}
+	// The cap stops before a malformed tail is ever decoded
+	jsonl := strings.Join(lines, "\n") + "\nnot json"
+	array := "[" + strings.Join(lines, ",") + ",not json]"
+
+	tests := []struct {
+		name string
+		data string
+		max  int
+		want int
+	}{
+		{"jsonl", jsonl, 2, 3},
+		{"array", array, 2, 3},
+		{"cap of one", jsonl, 1, 2},
+		{"cap above count", strings.Join(lines, "\n"), 10, 5},
+		{"no cap", strings.Join(lines, "\n"), 0, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := parseNucleiJSON([]byte(tt.data), "https://example.com", NucleiOptions{MaxFindings: tt.max})
+			if err != nil {
+				t.Fatalf("parseNucleiJSON: %v", err)
+			}
+			if len(findings) != tt.want {
+				t.Errorf("got %d findings, want %d", len(findings), tt.want)
+			}
+		})
+	}
+}
+
+func findingIDs(findings []schema.Finding) []string {
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
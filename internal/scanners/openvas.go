@@ -0,0 +1,169 @@
+package scanners
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+type openvasResult struct {
+	Name string `xml:"name"`
+	Host struct {
+		IP       string `xml:",chardata"`
+		Hostname string `xml:"hostname"`
+	} `xml:"host"`
+	Port string `xml:"port"`
+	NVT  struct {
+		OID      string `xml:"oid,attr"`
+		Name     string `xml:"name"`
+		Family   string `xml:"family"`
+		Tags     string `xml:"tags"`
+		Solution string `xml:"solution"`
+		Refs     []struct {
+			Type string `xml:"type,attr"`
+			ID   string `xml:"id,attr"`
+		} `xml:"refs>ref"`
+	} `xml:"nvt"`
+	Threat      string `xml:"threat"`
+	Severity    string `xml:"severity"`
+	Description string `xml:"description"`
+}
+
+// ImportOpenVAS reads a GVM/OpenVAS XML report and normalizes its results. With
+// an empty target each finding's Target is the host it was found on.
+func ImportOpenVAS(xmlPath string, target string) ([]schema.Finding, error) {
+	fh, err := os.Open(xmlPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open OpenVAS report: %w", err)
+	}
+	defer fh.Close()
+	return parseOpenVASXML(fh, target)
+}
+
+func parseOpenVASXML(r io.Reader, target string) ([]schema.Finding, error) {
+	// Results sit at different depths in GMP responses and GSA exports, so
+	// pick every <result> directly inside a <results> element
+	dec := xml.NewDecoder(r)
+	var stack []string
+	var findings []schema.Finding
+	sawReport := false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse OpenVAS XML: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "report" {
+				sawReport = true
+			}
+			if t.Name.Local == "result" && len(stack) > 0 && stack[len(stack)-1] == "results" {
+				var res openvasResult
+				if err := dec.DecodeElement(&res, &t); err != nil {
+					return nil, fmt.Errorf("failed to parse OpenVAS XML: %w", err)
+				}
+				if f, ok := openvasFinding(res, target); ok {
+					findings = append(findings, f)
+				}
+				continue
+			}
+			stack = append(stack, t.Name.Local)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	if !sawReport {
+		return nil, errors.New("not an OpenVAS/GVM report: no <report> element")
+	}
+	return findings, nil
+}
+
+// openvasFinding converts one result; false positives (negative severity) are dropped
+func openvasFinding(res openvasResult, target string) (schema.Finding, bool) {
+	score, err := strconv.ParseFloat(strings.TrimSpace(res.Severity), 64)
+	if err != nil {
+		score = 0
+	}
+	if score < 0 || strings.EqualFold(res.Threat, "False Positive") {
+		return schema.Finding{}, false
+	}
+
+	host := strings.TrimSpace(res.Host.IP)
+	if name := strings.TrimSpace(res.Host.Hostname); name != "" {
+		host = name
+	}
+	if target == "" {
+		target = host
+	}
+	tags := parseOpenVASTags(res.NVT.Tags)
+	name := strings.TrimSpace(fallbackStr(res.NVT.Name, res.Name))
+
+	f := schema.Finding{
+		ID:             "openvas-" + res.NVT.OID,
+		Target:         target,
+		Scanner:        "openvas",
+		Template:       "openvas-" + res.NVT.OID,
+		Severity:       openvasSeverity(score),
+		CVSS:           score,
+		Description:    name,
+		Evidence:       strings.TrimSpace(host + " " + res.Port),
+		Recommendation: strings.TrimSpace(fallbackStr(res.NVT.Solution, tags["solution"])),
+	}
+	if summary := tags["summary"]; summary != "" {
+		f.Description += ": " + summary
+	}
+	if detail := strings.Join(strings.Fields(res.Description), " "); detail != "" {
+		f.Evidence += ": " + detail
+	}
+	if fam := strings.TrimSpace(res.NVT.Family); fam != "" {
+		f.Tags = append(f.Tags, strings.ToLower(fam))
+	}
+	for _, ref := range res.NVT.Refs {
+		switch strings.ToLower(ref.Type) {
+		case "cve":
+			f.Tags = append(f.Tags, strings.ToUpper(ref.ID))
+		case "cwe":
+			f.Tags = append(f.Tags, "cwe:"+strings.TrimPrefix(strings.ToLower(ref.ID), "cwe-"))
+		case "url":
+			f.Tags = append(f.Tags, ref.ID)
+		}
+	}
+	return f, true
+}
+
+// parseOpenVASTags splits an NVT's "key=value|key=value" tag string
+func parseOpenVASTags(s string) map[string]string {
+	tags := map[string]string{}
+	for _, part := range strings.Split(s, "|") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			tags[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return tags
+}
+
+// openvasSeverity maps a GVM severity score (CVSS) to our severities
+func openvasSeverity(score float64) string {
+	switch {
+	case score >= 9:
+		return "critical"
+	case score >= 7:
+		return "high"
+	case score >= 4:
+		return "medium"
+	case score > 0:
+		return "low"
+	}
+	return "info"
+}
@@ -0,0 +1,96 @@
+package scanners
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"sync"
+)
+
+// Progress is a live update parsed from a scanner's streaming output
+type Progress struct {
+	Scanner string
+	// Severity is set when the update reports a new finding
+	Severity string
+	// Templates, Requests, Total and Percent come from periodic stats lines
+	Templates int
+	Requests  int
+	Total     int
+	Percent   int
+}
+
+// ProgressFunc receives progress updates; it may be called from several goroutines
+type ProgressFunc func(Progress)
+
+// progressWriter consumes nuclei's JSONL results and -stats-json lines and emits
+// Progress updates instead of echoing the raw console output
+type progressWriter struct {
+	mu      sync.Mutex
+	scanner string
+	fn      ProgressFunc
+	buf     []byte
+}
+
+func newProgressWriter(scanner string, fn ProgressFunc) *progressWriter {
+	return &progressWriter{scanner: scanner, fn: fn}
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.handle(bytes.TrimSpace(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *progressWriter) handle(line []byte) {
+	if len(line) == 0 || line[0] != '{' {
+		return
+	}
+	var m map[string]any
+	if err := json.Unmarshal(line, &m); err != nil {
+		return
+	}
+
+	// A streamed result
+	if _, ok := m["template-id"]; ok {
+		sev := "info"
+		if info, ok := m["info"].(map[string]any); ok {
+			if s, ok := info["severity"].(string); ok && s != "" {
+				sev = s
+			}
+		}
+		w.fn(Progress{Scanner: w.scanner, Severity: sev})
+		return
+	}
+
+	// A stats line
+	if _, ok := m["percent"]; ok {
+		w.fn(Progress{
+			Scanner:   w.scanner,
+			Templates: statInt(m["templates"]),
+			Requests:  statInt(m["requests"]),
+			Total:     statInt(m["total"]),
+			Percent:   statInt(m["percent"]),
+		})
+	}
+}
+
+// statInt reads nuclei stats values, which are sometimes strings and sometimes numbers
+func statInt(v any) int {
+	switch x := v.(type) {
+	case float64:
+		return int(x)
+	case string:
+		n, _ := strconv.Atoi(x)
+		return n
+	}
+	return 0
+}
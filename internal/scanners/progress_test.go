@@ -0,0 +1,69 @@
+package scanners
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestProgressWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   []Progress
+	}{
+		{
+			"stats line",
+			[]string{`{"duration":"0:00:05","templates":"120","requests":"40","total":"400","percent":"10"}` + "\n"},
+			[]Progress{{Scanner: "nuclei", Templates: 120, Requests: 40, Total: 400, Percent: 10}},
+		},
+		{
+			"numeric stats",
+			[]string{`{"templates":120,"requests":200,"total":400,"percent":50}` + "\n"},
+			[]Progress{{Scanner: "nuclei", Templates: 120, Requests: 200, Total: 400, Percent: 50}},
+		},
+		{
+			"result",
+			[]string{`{"template-id":"git-config","info":{"severity":"high"}}` + "\n"},
+			[]Progress{{Scanner: "nuclei", Severity: "high"}},
+		},
+		{
+			"result without severity",
+			[]string{`{"template-id":"t1","info":{}}` + "\n" + `{"template-id":"t2"}` + "\n"},
+			[]Progress{{Scanner: "nuclei", Severity: "info"}, {Scanner: "nuclei", Severity: "info"}},
+		},
+		{
+			"line split across writes",
+			[]string{`{"template-id":"git-con`, `fig","info":{"severity":"critical"}}` + "\n" + `{"percent":"7`, `5"}` + "\n"},
+			[]Progress{{Scanner: "nuclei", Severity: "critical"}, {Scanner: "nuclei", Percent: 75}},
+		},
+		{
+			"unterminated line",
+			[]string{`{"template-id":"t1","info":{"severity":"low"}}`},
+			nil,
+		},
+		{
+			"console noise",
+			[]string{"[INF] Using Nuclei Engine 3.2.0\n", "\n", "not json {\n", `{"matched-at":"x"}` + "\n", "{broken\n"},
+			nil,
+		},
+		{
+			"crlf",
+			[]string{`{"template-id":"t1","info":{"severity":"medium"}}` + "\r\n"},
+			[]Progress{{Scanner: "nuclei", Severity: "medium"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Progress
+			w := newProgressWriter("nuclei", func(p Progress) { got = append(got, p) })
+			for _, s := range tt.writes {
+				if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+					t.Fatalf("Write = %d, %v", n, err)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package scanners
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// Scanner is implemented by every scan engine yoro can drive
+type Scanner interface {
+	Name() string
+	Run(ctx context.Context, target string) ([]schema.Finding, error)
+}
+
+// CommandLiner is implemented by scanners that wrap an external binary and can
+// show the (redacted) command they would execute, e.g. for --dry-run
+type CommandLiner interface {
+	CommandLine(target string) string
+}
+
+// Options carries the CLI settings a scanner may need when it is built
+type Options struct {
+	Nuclei  NucleiOptions
+	Masscan MasscanOptions
+	TLS     TLSOptions
+	// Stdout and Stderr receive external tools' console output (os.Stdout/os.Stderr when nil)
+	Stdout io.Writer
+	Stderr io.Writer
+	// Progress, when set, receives live updates from scanners that stream them;
+	// their raw console output is consumed instead of echoed
+	Progress ProgressFunc
+	// RawDir, when set, receives a copy of each tool's raw report as <scanner>.raw.json
+	RawDir string
+}
+
+// streams is where an external tool's console output and raw report go
+type streams struct {
+	stdout io.Writer
+	stderr io.Writer
+	rawDir string
+}
+
+var defaultStreams = streams{stdout: os.Stdout, stderr: os.Stderr}
+
+func (o Options) streams() streams {
+	st := defaultStreams
+	if o.Stdout != nil {
+		st.stdout = o.Stdout
+	}
+	if o.Stderr != nil {
+		st.stderr = o.Stderr
+	}
+	st.rawDir = o.RawDir
+	return st
+}
+
+// killWait is how long a cancelled tool's leftover child processes may keep its
+// output open before Wait gives up on them
+const killWait = 5 * time.Second
+
+func (st streams) attach(cmd *exec.Cmd) {
+	cmd.Stdout = st.stdout
+	cmd.Stderr = st.stderr
+	cmd.WaitDelay = killWait
+}
+
+// keepRaw saves a tool's raw report into the raw directory, if one is set
+func (st streams) keepRaw(scanner string, data []byte) error {
+	if st.rawDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(st.rawDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(st.rawDir, scanner+".raw.json"), data, 0o644); err != nil {
+		return fmt.Errorf("failed to save raw %s output: %w", scanner, err)
+	}
+	return nil
+}
+
+// Factory builds a configured scanner from Options
+type Factory func(opts Options) Scanner
+
+// Info describes a registered scanner
+type Info struct {
+	Name        string
+	Description string
+	// Binary is the external executable required, empty for built-in scanners
+	Binary string
+	// TargetType is the kind of target accepted: "url" or "image"
+	TargetType string
+	// StreamsProgress is true when the scanner reports Options.Progress updates
+	StreamsProgress bool
+	// DefaultTimeout bounds a run unless --scanner-timeout overrides it; zero means no limit
+	DefaultTimeout time.Duration
+}
+
+type registration struct {
+	info    Info
+	factory Factory
+}
+
+var registry = map[string]registration{}
+
+// Register makes a scanner selectable via --scanners; call it from init()
+func Register(info Info, factory Factory) {
+	name := strings.ToLower(info.Name)
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("scanners: %q registered twice", name))
+	}
+	if info.TargetType == "" {
+		info.TargetType = "url"
+	}
+	registry[name] = registration{info: info, factory: factory}
+}
+
+// Lookup returns the registration info for name
+func Lookup(name string) (Info, bool) {
+	r, ok := registry[strings.ToLower(name)]
+	return r.info, ok
+}
+
+// New builds the scanner registered under name
+func New(name string, opts Options) (Scanner, error) {
+	r, ok := registry[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown scanner %q (available: %s)", name, strings.Join(names(), ", "))
+	}
+	return r.factory(opts), nil
+}
+
+// ListScanners returns all registered scanners sorted by name
+func ListScanners() []Info {
+	out := make([]Info, 0, len(registry))
+	for _, r := range registry {
+		out = append(out, r.info)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func names() []string {
+	var out []string
+	for _, info := range ListScanners() {
+		out = append(out, info.Name)
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package scanners
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// RetryPolicy controls how often a failed scanner run is repeated
+type RetryPolicy struct {
+	// Retries is the number of extra attempts after the first failure
+	Retries int
+	// Backoff is the wait before the first retry; it doubles on every further attempt
+	Backoff time.Duration
+	// OnRetry, when set, is called before each retry
+	OnRetry func(attempt int, wait time.Duration, err error)
+}
+
+// RunWithRetry runs s against target, retrying transient failures with exponential backoff
+func RunWithRetry(ctx context.Context, s Scanner, target string, p RetryPolicy) ([]schema.Finding, error) {
+	wait := p.Backoff
+	for attempt := 1; ; attempt++ {
+		findings, err := s.Run(ctx, target)
+		if err == nil || attempt > p.Retries || !Retryable(err) || ctx.Err() != nil {
+			return findings, err
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(attempt+1, wait, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// Retryable reports whether err looks transient: the tool started but exited with
+// an error. Misconfiguration is not retried, since every attempt would fail the same
+// way: a missing binary, exit code 2 (the conventional usage error) or stderr
+// complaining about flags, arguments or templates.
+func Retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return false
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	if exitErr.ExitCode() == usageExitCode {
+		return false
+	}
+	var se *ScannerError
+	if errors.As(err, &se) && usageError.MatchString(se.Stderr) {
+		return false
+	}
+	return true
+}
+
+// usageExitCode is what flag parsers and most CLI tools exit with on bad usage
+const usageExitCode = 2
+
+// usageError matches the stderr of a tool rejecting its command line or configuration
+var usageError = regexp.MustCompile(`(?i)flag provided but not defined|unknown (?:shorthand )?(?:flag|option|command)|invalid (?:argument|flag|option|value)|(?:could not|cannot|failed to|unable to) parse|parse error|usage:|no templates (?:provided|found)|could not find template`)
@@ -0,0 +1,59 @@
+package scanners
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+func TestRetryable(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	tests := []struct {
+		name   string
+		script string
+		want   bool
+	}{
+		{"transient failure", "echo 'connection reset by peer' >&2; exit 1", true},
+		{"killed", "kill -9 $$", true},
+		{"usage exit code", "echo 'something odd' >&2; exit 2", false},
+		{"undefined flag", "echo 'flag provided but not defined: -bogus' >&2; exit 1", false},
+		{"unknown flag", "echo 'Error: unknown flag: --bogus' >&2; exit 1", false},
+		{"parse error", "echo '[FTL] Could not parse template: yaml: line 3' >&2; exit 1", false},
+		{"no templates", "echo '[FTL] Could not run nuclei: no templates provided for scan' >&2; exit 1", false},
+		{"usage text", "echo 'Usage: tool [flags] target' >&2; exit 1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			cmd := exec.Command("sh", "-c", tt.script)
+			tail := streams{stdout: &bytes.Buffer{}, stderr: &stderr}.captureStderr(cmd)
+			err := newScannerError("fake", cmd.Run(), tail)
+			if got := Retryable(err); got != tt.want {
+				t.Errorf("Retryable(%v, stderr %q) = %v, want %v", err, err.Stderr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryableNonExitErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"missing binary", newScannerError("fake", exec.Command("yoro-no-such-tool").Run(), nil)},
+		{"canceled", fmt.Errorf("run: %w", context.Canceled)},
+		{"deadline", context.DeadlineExceeded},
+		{"plain error", fmt.Errorf("failed to write nuclei URL list")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Retryable(tt.err) {
+				t.Errorf("Retryable(%v) = true, want false", tt.err)
+			}
+		})
+	}
+}
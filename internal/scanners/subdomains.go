@@ -0,0 +1,131 @@
+package scanners
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+func init() {
+	Register(Info{
+		Name:        "subdomains",
+		Description: "Subdomain discovery with subfinder; each discovered host becomes an info finding",
+		Binary:      "subfinder",
+
+		DefaultTimeout: 10 * time.Minute,
+	}, func(o Options) Scanner { return subdomainsScanner{st: o.streams()} })
+}
+
+type subdomainsScanner struct{ st streams }
+
+func (subdomainsScanner) Name() string { return "subdomains" }
+
+// Run reports the subdomains of target's domain; IP targets have none and yield no findings
+func (s subdomainsScanner) Run(ctx context.Context, target string) ([]schema.Finding, error) {
+	domain := hostOf(target)
+	if net.ParseIP(domain) != nil {
+		return nil, nil
+	}
+	hosts, err := discoverSubdomains(ctx, domain, s.st)
+	if err != nil {
+		return nil, err
+	}
+	return subdomainFindings(target, domain, hosts), nil
+}
+
+func (subdomainsScanner) CommandLine(target string) string {
+	return SubdomainsCommandLine(hostOf(target))
+}
+
+// DiscoverSubdomains runs subfinder's passive enumeration for domain and returns
+// the discovered hosts, sorted and without duplicates
+func DiscoverSubdomains(ctx context.Context, domain string, opts Options) ([]string, error) {
+	return discoverSubdomains(ctx, domain, opts.streams())
+}
+
+func discoverSubdomains(ctx context.Context, domain string, st streams) ([]string, error) {
+	tmpFile := filepath.Join(os.TempDir(), fmt.Sprintf("subfinder_%d.json", time.Now().UnixNano()))
+	defer os.Remove(tmpFile)
+
+	cmd := exec.CommandContext(ctx, "subfinder", subfinderArgs(domain, tmpFile)...)
+	tail := st.captureStderr(cmd)
+	if err := cmd.Run(); err != nil {
+		return nil, newScannerError("subfinder", err, tail)
+	}
+	data, err := os.ReadFile(tmpFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subfinder output: %w", err)
+	}
+	if err := st.keepRaw("subdomains", data); err != nil {
+		return nil, err
+	}
+	return parseSubfinderJSON(data, domain), nil
+}
+
+func subfinderArgs(domain, outFile string) []string {
+	return []string{"-d", domain, "-silent", "-oJ", "-o", outFile}
+}
+
+// SubdomainsCommandLine renders the subfinder invocation for display
+func SubdomainsCommandLine(domain string) string {
+	return "subfinder " + strings.Join(subfinderArgs(domain, "<tmp>.json"), " ")
+}
+
+// parseSubfinderJSON reads subfinder's JSON lines ({"host": ...}), keeping only
+// hosts under domain; lines that are not JSON are taken as bare host names
+func parseSubfinderJSON(data []byte, domain string) []string {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	seen := map[string]bool{}
+	var hosts []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		host := line
+		var rec struct {
+			Host string `json:"host"`
+		}
+		if json.Unmarshal([]byte(line), &rec) == nil {
+			host = rec.Host
+		}
+		host = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+		if host == domain || !strings.HasSuffix(host, "."+domain) || seen[host] {
+			continue
+		}
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+func subdomainFindings(target, domain string, hosts []string) []schema.Finding {
+	findings := make([]schema.Finding, 0, len(hosts))
+	for _, h := range hosts {
+		findings = append(findings, schema.Finding{
+			ID:             "subdomain-" + h,
+			Target:         target,
+			Scanner:        "subdomains",
+			Template:       "subdomain-discovered",
+			Severity:       "info",
+			Description:    fmt.Sprintf("Discovered subdomain %s of %s", h, domain),
+			Evidence:       h,
+			Recommendation: "Confirm the host is expected and maintained; scan it too with --expand-subdomains",
+			Tags:           []string{"discovery", "subdomain"},
+		})
+	}
+	return findings
+}
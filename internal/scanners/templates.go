@@ -0,0 +1,84 @@
+package scanners
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// UpdateNucleiTemplates runs `nuclei -update-templates`, sending its output to opts' streams
+func UpdateNucleiTemplates(ctx context.Context, opts Options) error {
+	cmd := exec.CommandContext(ctx, "nuclei", "-update-templates")
+	opts.streams().attach(cmd)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to update nuclei templates: %w", err)
+	}
+	return nil
+}
+
+var templatesVersion = regexp.MustCompile(`(?i)templates version:?\s*(v?[0-9][0-9A-Za-z.\-]*)`)
+
+// NucleiTemplatesVersion returns the installed nuclei-templates version, "" when none are installed
+func NucleiTemplatesVersion(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "nuclei", "-templates-version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to read nuclei templates version: %w", err)
+	}
+	if m := templatesVersion.FindStringSubmatch(string(out)); m != nil {
+		return m[1], nil
+	}
+	return "", nil
+}
+
+// ListNucleiTemplates asks nuclei (-tl) which templates the selection in opts.Nuclei
+// matches, without sending any request, and returns their IDs in nuclei's order;
+// nuclei's log output goes to opts' stderr
+func ListNucleiTemplates(ctx context.Context, opts Options) ([]string, error) {
+	args := append([]string{"-tl", "-silent", "-no-color"}, nucleiSelectionArgs(opts.Nuclei)...)
+	cmd := exec.CommandContext(ctx, "nuclei", args...)
+	tail := opts.streams().captureStderr(cmd)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil, newScannerError("nuclei", fmt.Errorf("failed to list nuclei templates: %w", err), tail)
+	}
+	return parseTemplateList(stdout.Bytes()), nil
+}
+
+var logLine = regexp.MustCompile(`^\[(INF|WRN|ERR|FTL|DBG|VER)\]`)
+
+// parseTemplateList takes the template IDs from `nuclei -tl` output, which lists
+// template paths (or bare IDs on some versions), one per line
+func parseTemplateList(out []byte) []string {
+	var ids []string
+	seen := map[string]bool{}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || logLine.MatchString(line) {
+			continue
+		}
+		fields := strings.Fields(line)
+		field := fields[len(fields)-1]
+		for _, f := range fields {
+			if ext := path.Ext(f); ext == ".yaml" || ext == ".yml" {
+				field = f
+				break
+			}
+		}
+		id := strings.TrimSuffix(path.Base(strings.ReplaceAll(field, "\\", "/")), path.Ext(field))
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,51 @@
+package scanners
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Timeouts maps scanner names to run time limits; the "" key applies to all other scanners
+type Timeouts map[string]time.Duration
+
+// ParseTimeouts parses a --scanner-timeout value such as "30m" or "nuclei=300s,nmap=120s".
+// A bare duration sets the limit for every scanner not named explicitly; 0 disables the limit.
+func ParseTimeouts(spec string) (Timeouts, error) {
+	t := Timeouts{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, named := strings.Cut(part, "=")
+		if !named {
+			name, value = "", part
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if named {
+			if _, ok := Lookup(name); !ok {
+				return nil, fmt.Errorf("invalid --scanner-timeout %q: unknown scanner %q", part, name)
+			}
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid --scanner-timeout %q: expected a duration such as 300s or 10m", part)
+		}
+		t[name] = d
+	}
+	return t, nil
+}
+
+// For returns the time limit for the named scanner, falling back to its registered default
+func (t Timeouts) For(name string) time.Duration {
+	name = strings.ToLower(name)
+	if d, ok := t[name]; ok {
+		return d
+	}
+	if d, ok := t[""]; ok {
+		return d
+	}
+	info, _ := Lookup(name)
+	return info.DefaultTimeout
+}
@@ -0,0 +1,39 @@
+package scanners
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSOptions configure certificate verification for the built-in HTTP checks
+type TLSOptions struct {
+	// InsecureSkipVerify accepts any certificate (--insecure-skip-verify)
+	InsecureSkipVerify bool
+	// CAFile is a PEM bundle trusted in addition to the system roots (--ca-file),
+	// for targets with certificates from an internal CA
+	CAFile string
+}
+
+// Config returns the tls.Config for o; the zero TLSOptions verify strictly against
+// the system roots
+func (o TLSOptions) Config() (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: o.InsecureSkipVerify}
+	if o.CAFile == "" {
+		return cfg, nil
+	}
+	pem, err := os.ReadFile(o.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --ca-file: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("--ca-file %s holds no PEM certificates", o.CAFile)
+	}
+	cfg.RootCAs = pool
+	return cfg, nil
+}
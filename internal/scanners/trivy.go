@@ -0,0 +1,134 @@
+package scanners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+func init() {
+	Register(Info{
+		Name:        "trivy",
+		Description: "Container image vulnerability scanner",
+		Binary:      "trivy",
+		TargetType:  "image",
+
+		DefaultTimeout: 30 * time.Minute,
+	}, func(o Options) Scanner { return trivyScanner{st: o.streams()} })
+}
+
+type trivyScanner struct{ st streams }
+
+func (trivyScanner) Name() string { return "trivy" }
+
+func (s trivyScanner) Run(ctx context.Context, target string) ([]schema.Finding, error) {
+	return runTrivy(ctx, target, s.st)
+}
+
+func (trivyScanner) CommandLine(target string) string { return TrivyCommandLine(target) }
+
+func (trivyScanner) Version(ctx context.Context) (string, error) {
+	return probeVersion(ctx, "trivy", "--version")
+}
+
+// RunTrivy scans a container image with `trivy image` and returns normalized findings
+func RunTrivy(target string) ([]schema.Finding, error) {
+	return runTrivy(context.Background(), target, defaultStreams)
+}
+
+func runTrivy(ctx context.Context, target string, st streams) ([]schema.Finding, error) {
+	tmpFile := filepath.Join(os.TempDir(), fmt.Sprintf("trivy_%d.json", time.Now().UnixNano()))
+	defer os.Remove(tmpFile)
+
+	cmd := exec.CommandContext(ctx, "trivy", trivyArgs(target, tmpFile)...)
+	tail := st.captureStderr(cmd)
+
+	if err := cmd.Run(); err != nil {
+		return nil, newScannerError("trivy", err, tail)
+	}
+
+	data, err := os.ReadFile(tmpFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trivy output: %w", err)
+	}
+	if err := st.keepRaw("trivy", data); err != nil {
+		return nil, err
+	}
+	return parseTrivyJSON(data, target)
+}
+
+func trivyArgs(image, outFile string) []string {
+	return []string{"image", "-f", "json", "-o", outFile, image}
+}
+
+// TrivyCommandLine renders the trivy invocation for display
+func TrivyCommandLine(image string) string {
+	return "trivy " + strings.Join(trivyArgs(image, "<tmp>.json"), " ")
+}
+
+type trivyReport struct {
+	Results []struct {
+		Target          string `json:"Target"`
+		Vulnerabilities []struct {
+			VulnerabilityID  string `json:"VulnerabilityID"`
+			PkgName          string `json:"PkgName"`
+			InstalledVersion string `json:"InstalledVersion"`
+			FixedVersion     string `json:"FixedVersion"`
+			Severity         string `json:"Severity"`
+			Title            string `json:"Title"`
+			Description      string `json:"Description"`
+			CVSS             map[string]struct {
+				V3Score float64 `json:"V3Score"`
+			} `json:"CVSS"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+func parseTrivyJSON(data []byte, target string) ([]schema.Finding, error) {
+	var rep trivyReport
+	if err := json.Unmarshal(data, &rep); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy JSON: %w", err)
+	}
+
+	var findings []schema.Finding
+	for _, r := range rep.Results {
+		for _, v := range r.Vulnerabilities {
+			sev := strings.ToLower(v.Severity)
+			if sev == "unknown" || sev == "" {
+				sev = "info"
+			}
+			f := schema.Finding{
+				ID:          v.VulnerabilityID,
+				Target:      target,
+				Scanner:     "trivy",
+				Template:    v.VulnerabilityID,
+				Severity:    sev,
+				Description: strings.TrimSpace(v.Title + ": " + v.Description),
+				Evidence:    fmt.Sprintf("%s %s (%s)", v.PkgName, v.InstalledVersion, r.Target),
+				Tags:        []string{v.VulnerabilityID},
+			}
+			if v.Title == "" {
+				f.Description = v.Description
+			}
+			if v.FixedVersion != "" {
+				f.Recommendation = fmt.Sprintf("Upgrade %s to %s", v.PkgName, v.FixedVersion)
+			} else {
+				f.Recommendation = fmt.Sprintf("No fixed version of %s is available yet; monitor the advisory or replace the package", v.PkgName)
+			}
+			for _, c := range v.CVSS {
+				if c.V3Score > f.CVSS {
+					f.CVSS = c.V3Score
+				}
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
@@ -0,0 +1,43 @@
+package scanners
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// Versioner is implemented by scanners that can report the version of their tool
+type Versioner interface {
+	Version(ctx context.Context) (string, error)
+}
+
+// Meta describes how s is (or was) run against target, for results.json.
+// The version is left empty when it cannot be determined.
+func Meta(ctx context.Context, s Scanner, target string) schema.ScannerMeta {
+	m := schema.ScannerMeta{Name: s.Name(), Command: "(built-in)"}
+	if cl, ok := s.(CommandLiner); ok {
+		m.Command = cl.CommandLine(target)
+	}
+	if v, ok := s.(Versioner); ok {
+		if version, err := v.Version(ctx); err == nil {
+			m.Version = version
+		}
+	}
+	return m
+}
+
+var versionPattern = regexp.MustCompile(`v?\d+\.\d+(\.\d+)?([-+][0-9A-Za-z.]+)?`)
+
+// probeVersion runs a tool's version command and extracts the first version number it prints
+func probeVersion(ctx context.Context, bin string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, bin, args...).CombinedOutput()
+	if err != nil {
+		return "", err
+	}
+	return versionPattern.FindString(string(out)), nil
+}
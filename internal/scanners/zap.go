@@ -0,0 +1,149 @@
+package scanners
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// zapBaseline is the ZAP baseline script shipped in the official ZAP images
+const zapBaseline = "zap-baseline.py"
+
+func init() {
+	Register(Info{
+		Name:        "zap",
+		Description: "OWASP ZAP baseline (passive) web application scan",
+		Binary:      zapBaseline,
+
+		DefaultTimeout: time.Hour,
+	}, func(o Options) Scanner { return zapScanner{st: o.streams()} })
+}
+
+type zapScanner struct{ st streams }
+
+func (zapScanner) Name() string { return "zap" }
+
+func (s zapScanner) Run(ctx context.Context, target string) ([]schema.Finding, error) {
+	return runZAP(ctx, target, s.st)
+}
+
+func (zapScanner) CommandLine(target string) string { return ZAPCommandLine(target) }
+
+// RunZAP executes the ZAP baseline scan with a JSON report and returns normalized findings
+func RunZAP(target string) ([]schema.Finding, error) {
+	return runZAP(context.Background(), target, defaultStreams)
+}
+
+func runZAP(ctx context.Context, target string, st streams) ([]schema.Finding, error) {
+	// zap-baseline joins -J onto /zap/wrk, so an absolute path escapes it
+	tmpFile := filepath.Join(os.TempDir(), fmt.Sprintf("zap_%d.json", time.Now().UnixNano()))
+	defer os.Remove(tmpFile)
+
+	cmd := exec.CommandContext(ctx, zapBaseline, zapArgs(target, tmpFile)...)
+	tail := st.captureStderr(cmd)
+
+	// zap-baseline exits 1 when alerts FAIL and 2 when they WARN; both still produce a report
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || (exitErr.ExitCode() != 1 && exitErr.ExitCode() != 2) {
+			return nil, newScannerError("zap", err, tail)
+		}
+	}
+
+	data, err := os.ReadFile(tmpFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zap output: %w", err)
+	}
+	if err := st.keepRaw("zap", data); err != nil {
+		return nil, err
+	}
+	return parseZAPJSON(data, target)
+}
+
+func zapArgs(target, reportFile string) []string {
+	return []string{"-t", target, "-J", reportFile}
+}
+
+// ZAPCommandLine renders the ZAP invocation for display
+func ZAPCommandLine(target string) string {
+	return zapBaseline + " " + strings.Join(zapArgs(target, "<tmp>.json"), " ")
+}
+
+type zapReport struct {
+	Site []struct {
+		Name   string     `json:"@name"`
+		Alerts []zapAlert `json:"alerts"`
+	} `json:"site"`
+}
+
+type zapAlert struct {
+	PluginID  string `json:"pluginid"`
+	Alert     string `json:"alert"`
+	RiskCode  string `json:"riskcode"`
+	Desc      string `json:"desc"`
+	Solution  string `json:"solution"`
+	CWEID     string `json:"cweid"`
+	Instances []struct {
+		URI string `json:"uri"`
+	} `json:"instances"`
+}
+
+// zapRisk maps ZAP risk codes to our severities
+var zapRisk = map[string]string{"0": "info", "1": "low", "2": "medium", "3": "high"}
+
+func parseZAPJSON(data []byte, target string) ([]schema.Finding, error) {
+	var rep zapReport
+	if err := json.Unmarshal(data, &rep); err != nil {
+		return nil, fmt.Errorf("failed to parse zap JSON: %w", err)
+	}
+
+	var findings []schema.Finding
+	for _, site := range rep.Site {
+		for _, a := range site.Alerts {
+			sev, ok := zapRisk[a.RiskCode]
+			if !ok {
+				sev = "info"
+			}
+			f := schema.Finding{
+				ID:             "zap-" + a.PluginID,
+				Target:         target,
+				Scanner:        "zap",
+				Template:       "zap-" + a.PluginID,
+				Severity:       sev,
+				Description:    strings.TrimSpace(a.Alert + ": " + stripHTML(a.Desc)),
+				Recommendation: stripHTML(a.Solution),
+			}
+			// ZAP uses -1 or 0 for alerts without a CWE
+			if n, err := strconv.Atoi(a.CWEID); err == nil && n > 0 {
+				f.Tags = append(f.Tags, "cwe:"+a.CWEID)
+			}
+			if len(a.Instances) > 0 {
+				f.Evidence = a.Instances[0].URI
+				if n := len(a.Instances) - 1; n > 0 {
+					f.Evidence += fmt.Sprintf(" (+%d more URLs)", n)
+				}
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// stripHTML flattens the <p>-wrapped text ZAP puts in descriptions
+func stripHTML(s string) string {
+	s = htmlTag.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
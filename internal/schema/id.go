@@ -0,0 +1,13 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// StableID derives an ID from Template, Target and Evidence, so the same
+// occurrence gets the same ID in every scan
+func StableID(f Finding) string {
+	sum := sha256.Sum256([]byte(f.Template + "\x00" + f.Target + "\x00" + f.Evidence))
+	return hex.EncodeToString(sum[:8])
+}
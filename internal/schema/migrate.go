@@ -0,0 +1,27 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CurrentVersion is the results.json schema version written by this build.
+//
+//	0: files written before the version field existed
+//	1: schema_version added; severities are lowercase
+const CurrentVersion = 1
+
+// Migrate upgrades a decoded result from an older schema version in place.
+// Results from a newer version are rejected rather than misread.
+func Migrate(r *ScanResult) error {
+	if r.SchemaVersion > CurrentVersion {
+		return fmt.Errorf("results use schema version %d but this yoro only understands up to %d; please upgrade yoro", r.SchemaVersion, CurrentVersion)
+	}
+	if r.SchemaVersion < 1 {
+		for i := range r.Findings {
+			r.Findings[i].Severity = strings.ToLower(strings.TrimSpace(r.Findings[i].Severity))
+		}
+	}
+	r.SchemaVersion = CurrentVersion
+	return nil
+}
@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// severitySynonyms maps the vocabularies of other tools and custom templates to
+// the five severities
+var severitySynonyms = map[string]string{
+	"critical": "critical", "crit": "critical", "urgent": "critical", "emergency": "critical", "blocker": "critical", "fatal": "critical",
+	"high": "high", "important": "high", "major": "high", "serious": "high", "severe": "high", "error": "high",
+	"medium": "medium", "moderate": "medium", "med": "medium",
+	"low": "low", "minor": "low", "warning": "low", "warn": "low", "notice": "low",
+	"info": "info", "informational": "info", "information": "info", "none": "info", "note": "info", "unknown": "info",
+}
+
+// UnknownSeverity is called once for each distinct value NormalizeSeverity does
+// not recognize; the CLI sets it to print a warning
+var UnknownSeverity = func(raw string) {}
+
+var reportedSeverities sync.Map
+
+// NormalizeSeverity maps raw to critical, high, medium, low or info. Synonyms such
+// as moderate or important are translated, 0-4 are taken as levels (0 info to
+// 4 critical) and other numbers up to 10 as CVSS scores. An empty raw stays empty
+// so callers can fall back to the CVSS score; anything else unknown becomes info.
+func NormalizeSeverity(raw string) string {
+	sev, ok := normalizeSeverity(raw)
+	if !ok {
+		if _, seen := reportedSeverities.LoadOrStore(strings.ToLower(strings.TrimSpace(raw)), true); !seen {
+			UnknownSeverity(raw)
+		}
+	}
+	return sev
+}
+
+// normalizeSeverity is NormalizeSeverity without the warning; ok is false for
+// values it does not recognize
+func normalizeSeverity(raw string) (sev string, ok bool) {
+	key := strings.ToLower(strings.TrimSpace(raw))
+	if key == "" {
+		return "", true
+	}
+	if sev, ok := severitySynonyms[key]; ok {
+		return sev, true
+	}
+	if n, err := strconv.ParseFloat(key, 64); err == nil && n >= 0 && n <= 10 {
+		if level := int(n); float64(level) == n && level <= 4 && !strings.Contains(key, ".") {
+			return severityOrder[level], true
+		}
+		return CVSSSeverity(n), true
+	}
+	return "info", false
+}
+
+// severityOrder lists severities by rank, info first
+var severityOrder = []string{"info", "low", "medium", "high", "critical"}
+
+// CVSSSeverity maps a CVSS v3 base score to its qualitative rating
+func CVSSSeverity(score float64) string {
+	switch {
+	case score >= 9.0:
+		return "critical"
+	case score >= 7.0:
+		return "high"
+	case score >= 4.0:
+		return "medium"
+	case score > 0:
+		return "low"
+	}
+	return "info"
+}
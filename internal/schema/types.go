@@ -1,6 +1,11 @@
 package schema
 
-import "time"
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
 
 // Finding is a normalized vulnerability finding
 type Finding struct {
@@ -10,15 +15,128 @@ type Finding struct {
 	Template       string   `json:"template"`
 	Severity       string   `json:"severity"`
 	CVSS           float64  `json:"cvss,omitempty"`
+	CVSSVector     string   `json:"cvss_vector,omitempty"` // e.g. CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H
 	Description    string   `json:"description,omitempty"`
 	Evidence       string   `json:"evidence,omitempty"`
 	Recommendation string   `json:"recommendation,omitempty"`
 	Tags           []string `json:"tags,omitempty"`
+	// Suppressed findings are accepted risks: kept, but excluded from counts and score
+	Suppressed   bool   `json:"suppressed,omitempty"`
+	SuppressedBy string `json:"suppressed_by,omitempty"`
+	// RequestResponse is the HTTP exchange behind a web finding, so it can be verified
+	RequestResponse *HTTPExchange `json:"request_response,omitempty"`
+	// Raw is the scanner's original record, kept with --include-raw for debugging parsers
+	Raw json.RawMessage `json:"raw,omitempty"`
+}
+
+// HTTPExchange is a request and its response as raw HTTP text, with credentials
+// redacted and each side cut to a few KiB
+type HTTPExchange struct {
+	Request  string `json:"request"`
+	Response string `json:"response"`
+	// Truncated is set when either side was longer than what was kept
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// TechnologyTag marks findings that name a detected technology rather than a
+// weakness; reports list them apart from the findings table
+const TechnologyTag = "technology"
+
+// Authorization records who attested to being allowed to scan a target
+type Authorization struct {
+	Attestation string    `json:"attestation"`
+	Operator    string    `json:"operator"`
+	Target      string    `json:"target"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Scanner run outcomes recorded in ScannerMeta.Status
+const (
+	ScannerOK     = "ok"
+	ScannerFailed = "failed"
+)
+
+// ScannerMeta records which tool version and command line produced a result, and how the run went
+type ScannerMeta struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Command string `json:"command,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Error   string `json:"error,omitempty"`
+	// ExitCode and Stderr (its tail) describe a failed external tool
+	ExitCode int    `json:"exit_code,omitempty"`
+	Stderr   string `json:"stderr,omitempty"`
 }
 
 // ScanResult groups all findings for one run
 type ScanResult struct {
-	Target    string    `json:"target"`
-	Timestamp time.Time `json:"timestamp"`
-	Findings  []Finding `json:"findings"`
+	SchemaVersion int               `json:"schema_version,omitempty"`
+	Target        string            `json:"target"`
+	Labels        map[string]string `json:"labels,omitempty"` // e.g. env=prod, to tell environments apart
+	Timestamp     time.Time         `json:"timestamp"`
+	StartedAt     time.Time         `json:"started_at,omitzero"`
+	FinishedAt    time.Time         `json:"finished_at,omitzero"`
+	Authorization *Authorization    `json:"authorization,omitempty"`
+	ScannerMeta   []ScannerMeta     `json:"scanner_meta,omitempty"`
+	Truncated     bool              `json:"truncated,omitempty"` // findings were capped at MaxFindings (--max-findings)
+	MaxFindings   int               `json:"max_findings,omitempty"`
+	ScoreWeights  map[string]int    `json:"score_weights,omitempty"` // per-severity penalties the score was computed with
+	Findings      []Finding         `json:"findings"`
+
+	// ExcludedSeverities were dropped at scan time (--exclude-severity), so no
+	// findings of them are in Findings
+	ExcludedSeverities []string `json:"excluded_severities,omitempty"`
+}
+
+// EnvLabel is the label set by --env; it is also part of the result directory name
+const EnvLabel = "env"
+
+// LabelPairs returns the labels as "key=value" sorted by key
+func (r ScanResult) LabelPairs() []string {
+	pairs := make([]string, 0, len(r.Labels))
+	for k, v := range r.Labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// HasLabels reports whether r carries every label in want
+func (r ScanResult) HasLabels(want map[string]string) bool {
+	for k, v := range want {
+		if r.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// FailedScanners lists the scanners that did not complete; their findings are missing
+func (r ScanResult) FailedScanners() []string {
+	var names []string
+	for _, m := range r.ScannerMeta {
+		if m.Status == ScannerFailed {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
+// Duration is how long the scanners ran; zero for results written before timing was recorded
+func (r ScanResult) Duration() time.Duration {
+	if r.StartedAt.IsZero() || r.FinishedAt.Before(r.StartedAt) {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// severityRank orders severities from least to most serious
+var severityRank = map[string]int{"info": 0, "low": 1, "medium": 2, "high": 3, "critical": 4}
+
+// SeverityRank returns 0 (info) to 4 (critical), or -1 for an unknown severity
+func SeverityRank(sev string) int {
+	if r, ok := severityRank[strings.ToLower(strings.TrimSpace(sev))]; ok {
+		return r
+	}
+	return -1
 }
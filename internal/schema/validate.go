@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validate lists what is wrong with a decoded result: missing fields, unknown
+// severities, impossible scores or times. Reports still render most such results,
+// so this is for gating files produced outside yoro; nil means valid.
+func (r ScanResult) Validate() []string {
+	var problems []string
+	add := func(format string, args ...any) { problems = append(problems, fmt.Sprintf(format, args...)) }
+
+	if strings.TrimSpace(r.Target) == "" {
+		add("target is missing")
+	}
+	if r.Timestamp.IsZero() {
+		add("timestamp is missing or zero")
+	}
+	if !r.StartedAt.IsZero() && !r.FinishedAt.IsZero() && r.FinishedAt.Before(r.StartedAt) {
+		add("finished_at %s is before started_at %s", r.FinishedAt.Format("2006-01-02T15:04:05Z07:00"), r.StartedAt.Format("2006-01-02T15:04:05Z07:00"))
+	}
+	if r.Findings == nil {
+		add("findings is missing (use [] for a scan without findings)")
+	}
+	for _, sev := range r.ExcludedSeverities {
+		if SeverityRank(sev) < 0 {
+			add("excluded_severities: %q is not critical, high, medium, low or info", sev)
+		}
+	}
+	for i, m := range r.ScannerMeta {
+		if m.Name == "" {
+			add("scanner_meta[%d]: name is missing", i)
+		}
+		if m.Status != "" && m.Status != ScannerOK && m.Status != ScannerFailed {
+			add("scanner_meta[%d]: status %q is not %q or %q", i, m.Status, ScannerOK, ScannerFailed)
+		}
+	}
+
+	for i, f := range r.Findings {
+		where := fmt.Sprintf("findings[%d]", i)
+		if f.ID != "" {
+			where += " (" + f.ID + ")"
+		}
+		if f.ID == "" {
+			add("%s: id is missing", where)
+		}
+		if f.Target == "" {
+			add("%s: target is missing", where)
+		}
+		if f.Scanner == "" {
+			add("%s: scanner is missing", where)
+		}
+		switch sev := strings.TrimSpace(f.Severity); {
+		case sev == "" && f.CVSS == 0:
+			add("%s: severity is missing and there is no cvss score to rate it (read as info)", where)
+		case sev != "" && SeverityRank(sev) < 0:
+			read, _ := normalizeSeverity(sev)
+			add("%s: severity %q is not critical, high, medium, low or info (read as %s)", where, f.Severity, read)
+		}
+		if f.CVSS < 0 || f.CVSS > 10 {
+			add("%s: cvss %.1f is outside 0-10", where, f.CVSS)
+		}
+	}
+	return problems
+}
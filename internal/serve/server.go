@@ -0,0 +1,178 @@
+// Package serve shares scan result directories over HTTP: an index of the
+// available scans plus their reports and results files
+package serve
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	reportpkg "github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+)
+
+//go:embed templates/index.html.tmpl
+var indexHTMLTemplate string
+
+var indexTmpl = template.Must(template.New("index").Parse(indexHTMLTemplate))
+
+// servedFiles are the file name patterns shared from a scan directory; everything
+// else (audit log, stray files) stays private
+var servedFiles = []string{"results.json", "results.*.json", "report*.html", "*.pdf", "findings.ndjson", "manifest.json"}
+
+// privateFiles are never shared even when they match servedFiles: raw scanner
+// output keeps unredacted requests and headers, and the anonymize map turns
+// pseudonyms back into real hosts
+var privateFiles = []string{"*.raw.json", reportpkg.AnonymizeMapFile}
+
+// served reports whether the file called name may be listed and downloaded
+func served(name string) bool {
+	return !matchAny(privateFiles, name) && matchAny(servedFiles, name)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler serves the scans under Root
+type Handler struct {
+	Root string
+}
+
+// NewHandler returns a handler listing and serving the scans under root
+func NewHandler(root string) *Handler {
+	return &Handler{Root: root}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.URL.Path == "/" {
+		h.serveIndex(w)
+		return
+	}
+	h.serveFile(w, r)
+}
+
+// scanEntry is one row of the index page
+type scanEntry struct {
+	Dir    string
+	Target string
+	Labels string
+	Time   string
+	Score  int
+	Grade  string
+	Total  int
+	Files  []scanFile
+	Error  string
+
+	stamp time.Time
+}
+
+type scanFile struct {
+	Name string
+	URL  string
+}
+
+func (h *Handler) serveIndex(w http.ResponseWriter) {
+	scans, err := h.scans()
+	if err != nil {
+		http.Error(w, "failed to list scans", http.StatusInternalServerError)
+		return
+	}
+	var buf bytes.Buffer
+	err = indexTmpl.Execute(&buf, map[string]any{
+		"Scans":       scans,
+		"GeneratedAt": time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		http.Error(w, "failed to render index", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// scans lists every result directory under Root, newest first
+func (h *Handler) scans() ([]scanEntry, error) {
+	dirs, err := reportpkg.FindResultDirs(h.Root)
+	if err != nil {
+		return nil, err
+	}
+	var entries []scanEntry
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(h.Root, dir)
+		if err != nil {
+			continue
+		}
+		e := scanEntry{Dir: filepath.ToSlash(rel)}
+		res, err := reportpkg.LoadScanResult(dir)
+		if err != nil {
+			e.Error = err.Error()
+		} else {
+			e.Target, e.stamp = res.Target, res.Timestamp
+			e.Labels = strings.Join(res.LabelPairs(), ", ")
+			e.Time = res.Timestamp.UTC().Format(time.RFC3339)
+			e.Score, e.Grade = reportpkg.ScoreOf(res)
+			for _, f := range res.Findings {
+				if !f.Suppressed {
+					e.Total++
+				}
+			}
+		}
+		files, _ := os.ReadDir(dir)
+		for _, f := range files {
+			if f.Type().IsRegular() && served(f.Name()) {
+				e.Files = append(e.Files, scanFile{Name: f.Name(), URL: "/" + path.Join(e.Dir, f.Name())})
+			}
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].stamp.After(entries[j].stamp) })
+	return entries, nil
+}
+
+// serveFile serves a report or results file from a scan directory under Root
+func (h *Handler) serveFile(w http.ResponseWriter, r *http.Request) {
+	rel := path.Clean(r.URL.Path)
+	file := filepath.Join(h.Root, filepath.FromSlash(rel))
+	if !served(path.Base(rel)) {
+		http.NotFound(w, r)
+		return
+	}
+	// Only files that sit next to a results.json belong to a scan
+	if _, err := os.Stat(filepath.Join(filepath.Dir(file), "results.json")); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	info, err := os.Stat(file)
+	if err != nil || !info.Mode().IsRegular() {
+		http.NotFound(w, r)
+		return
+	}
+	fh, err := os.Open(file)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to open %s", path.Base(rel)), http.StatusInternalServerError)
+		return
+	}
+	defer fh.Close()
+	if path.Ext(rel) == ".ndjson" {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	http.ServeContent(w, r, info.Name(), info.ModTime(), fh)
+}
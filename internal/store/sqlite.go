@@ -0,0 +1,146 @@
+// Package store copies saved scan results into a SQLite database for ad-hoc SQL
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	// Pure-Go SQLite driver, so the binary still builds without cgo
+	_ "modernc.org/sqlite"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// schemaSQL creates the tables on first use. Timestamps are RFC3339 UTC text, so
+// they sort and compare correctly as strings.
+const schemaSQL = `
+CREATE TABLE IF NOT EXISTS scans (
+	scan_id   TEXT PRIMARY KEY,
+	target    TEXT NOT NULL,
+	timestamp TEXT NOT NULL,
+	score     INTEGER NOT NULL,
+	grade     TEXT NOT NULL,
+	total     INTEGER NOT NULL,
+	labels    TEXT,
+	imported  TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS findings (
+	scan_id    TEXT NOT NULL REFERENCES scans(scan_id) ON DELETE CASCADE,
+	target     TEXT NOT NULL,
+	timestamp  TEXT NOT NULL,
+	severity   TEXT NOT NULL,
+	template   TEXT NOT NULL,
+	cvss       REAL,
+	evidence   TEXT,
+	scanner    TEXT,
+	suppressed INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS findings_scan ON findings(scan_id);
+CREATE INDEX IF NOT EXISTS findings_target_time ON findings(target, timestamp);
+`
+
+// Open opens (creating when missing) the database at path and its tables
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := db.Exec(schemaSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tables in %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// ImportStats counts what Import did
+type ImportStats struct {
+	Scans    int
+	Findings int
+	Skipped  []string // result directories that could not be read
+}
+
+// Import loads every scan result under root into db. Scans are keyed by their
+// directory relative to root, so importing the same root again replaces those
+// rows instead of duplicating them.
+func Import(ctx context.Context, db *sql.DB, root string) (ImportStats, error) {
+	var st ImportStats
+	dirs, err := report.FindResultDirs(root)
+	if err != nil {
+		return st, err
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return st, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, dir := range dirs {
+		res, err := report.LoadScanResult(dir)
+		if err != nil {
+			st.Skipped = append(st.Skipped, fmt.Sprintf("%s: %v", dir, err))
+			continue
+		}
+		id, err := filepath.Rel(root, dir)
+		if err != nil {
+			id = dir
+		}
+		n, err := importScan(ctx, tx, filepath.ToSlash(id), res, now)
+		if err != nil {
+			return st, fmt.Errorf("failed to import %s: %w", dir, err)
+		}
+		st.Scans++
+		st.Findings += n
+	}
+	if err := tx.Commit(); err != nil {
+		return st, fmt.Errorf("failed to commit import: %w", err)
+	}
+	return st, nil
+}
+
+// importScan replaces the rows of one scan and returns how many findings it wrote
+func importScan(ctx context.Context, tx *sql.Tx, id string, res schema.ScanResult, now string) (int, error) {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM findings WHERE scan_id = ?`, id); err != nil {
+		return 0, err
+	}
+	var labels any
+	if len(res.Labels) > 0 {
+		data, err := json.Marshal(res.Labels)
+		if err != nil {
+			return 0, err
+		}
+		labels = string(data)
+	}
+	ts := res.Timestamp.UTC().Format(time.RFC3339)
+	s := report.Summarize(res)
+	if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO scans (scan_id, target, timestamp, score, grade, total, labels, imported)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, id, res.Target, ts, s.Score, s.Grade, s.Total, labels, now); err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO findings (scan_id, target, timestamp, severity, template, cvss, evidence, scanner, suppressed)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	for _, f := range res.Findings {
+		template := f.Template
+		if template == "" {
+			template = f.ID
+		}
+		var cvss any
+		if f.CVSS > 0 {
+			cvss = f.CVSS
+		}
+		if _, err := stmt.ExecContext(ctx, id, f.Target, ts, f.Severity, template, cvss, f.Evidence, f.Scanner, f.Suppressed); err != nil {
+			return 0, err
+		}
+	}
+	return len(res.Findings), nil
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/store"
+)
+
+func newDBCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Query saved scans with SQL through a local SQLite database",
+	}
+
+	imp := &cobra.Command{
+		Use:   "import",
+		Short: "Create or update a SQLite database with the scans and findings under an output root",
+		Long: `Create or update a SQLite database with a "scans" table (one row per scan
+directory) and a "findings" table (scan_id, target, timestamp, severity,
+template, cvss, evidence, scanner, suppressed). Scans are keyed by directory,
+so importing the same root again refreshes rows instead of duplicating them.`,
+		Example: `yoro db import --dir ./reports --db yoro.db
+sqlite3 yoro.db "SELECT target, severity, count(*) FROM findings GROUP BY 1, 2"`,
+		RunE: runDBImport,
+	}
+	imp.Flags().String("dir", "", "Output root holding the scan result directories (default --output)")
+	imp.Flags().String("db", "yoro.db", "SQLite database file to create or update")
+	_ = viper.BindPFlag("db.dir", imp.Flags().Lookup("dir"))
+	_ = viper.BindPFlag("db.path", imp.Flags().Lookup("db"))
+
+	cmd.AddCommand(imp)
+	return cmd
+}
+
+func runDBImport(cmd *cobra.Command, _ []string) error {
+	dir := viper.GetString("db.dir")
+	if dir == "" {
+		dir = viper.GetString("output")
+	}
+	path := viper.GetString("db.path")
+	if path == "" {
+		return usageErrorf("please provide --db")
+	}
+
+	db, err := store.Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	st, err := store.Import(cmd.Context(), db, dir)
+	for _, s := range st.Skipped {
+		logf("⚠️  Skipping %s\n", s)
+	}
+	if err != nil {
+		return err
+	}
+	logf("🗄️  Imported %d scans (%d findings) from %s into %s\n", st.Scans, st.Findings, dir, path)
+	return nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/scanners"
+)
+
+// Exit codes returned by yoro
+const (
+	ExitOK        = 0
+	ExitUsage     = 1 // invalid flags, arguments or configuration (and any unclassified error)
+	ExitScanner   = 2 // a scanner is missing or failed
+	ExitThreshold = 3 // the scan ran but findings reached the --fail-on severity
+	ExitTimeout   = 4 // the command ran past --timeout and was stopped
+)
+
+// Sentinel errors carried by command errors; test with errors.Is
+var (
+	ErrUsage     = errors.New("usage error")
+	ErrScanner   = errors.New("scanner error")
+	ErrThreshold = errors.New("findings above threshold")
+	ErrTimeout   = errors.New("timed out")
+)
+
+// exitError tags err with one of the sentinel kinds without changing its message
+type exitError struct {
+	kind error
+	err  error
+}
+
+func (e *exitError) Error() string   { return e.err.Error() }
+func (e *exitError) Unwrap() []error { return []error{e.kind, e.err} }
+
+func usageErrorf(format string, args ...any) error {
+	return &exitError{kind: ErrUsage, err: fmt.Errorf(format, args...)}
+}
+
+func withKind(kind, err error) error {
+	if err == nil || errors.Is(err, kind) {
+		return err
+	}
+	return &exitError{kind: kind, err: err}
+}
+
+// ExitCode maps an error returned by a command to the process exit code
+func ExitCode(err error) int {
+	var se *scanners.ScannerError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrTimeout):
+		return ExitTimeout
+	case errors.Is(err, ErrThreshold):
+		return ExitThreshold
+	case errors.Is(err, ErrScanner), errors.As(err, &se):
+		return ExitScanner
+	}
+	return ExitUsage
+}
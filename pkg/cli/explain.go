@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/explain"
+	reportpkg "github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+func newExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "explain",
+		Short:   "Explain a finding in plain language with impact and remediation",
+		Example: "yoro explain --from ./reports/example.com_20250101T000000Z --id http-missing-security-headers",
+		RunE:    runExplain,
+	}
+
+	cmd.Flags().String("from", "", "Scan result directory (contains results.json)")
+	cmd.Flags().String("id", "", "Finding ID or template ID to explain")
+	_ = viper.BindPFlag("explain.from", cmd.Flags().Lookup("from"))
+	_ = viper.BindPFlag("explain.id", cmd.Flags().Lookup("id"))
+	return cmd
+}
+
+func runExplain(_ *cobra.Command, _ []string) error {
+	from, id := viper.GetString("explain.from"), strings.TrimSpace(viper.GetString("explain.id"))
+	if from == "" || id == "" {
+		return usageErrorf("please provide --from and --id")
+	}
+	res, err := reportpkg.LoadScanResult(from)
+	if err != nil {
+		return err
+	}
+
+	var matches []schema.Finding
+	for _, f := range res.Findings {
+		if strings.EqualFold(f.ID, id) || strings.EqualFold(f.Template, id) {
+			matches = append(matches, f)
+		}
+	}
+	if len(matches) == 0 {
+		return usageErrorf("no finding with ID or template %q in %s", id, from)
+	}
+
+	f := matches[0]
+	g := explain.Explain(f)
+	out := os.Stdout
+	fmt.Fprintf(out, "%s\n%s\n\n", g.Title, strings.Repeat("=", len([]rune(g.Title))))
+	fmt.Fprintf(out, "Finding:   %s (%s, severity %s)\n", fallbackStr(f.ID, f.Template), f.Scanner, strings.ToUpper(f.Severity))
+	if f.Suppressed {
+		fmt.Fprintf(out, "Status:    accepted (%s)\n", f.SuppressedBy)
+	}
+	fmt.Fprintln(out)
+	printSection("What it means", g.Explanation)
+	printSection("Why it matters", g.Impact)
+	printSection("How to fix it", g.Remediation)
+
+	fmt.Fprintf(out, "Where it was found (%d):\n", len(matches))
+	for _, m := range matches {
+		fmt.Fprintf(out, "  - %s\n", fallbackStr(m.Evidence, m.Target))
+	}
+	if len(g.References) > 0 {
+		fmt.Fprintln(out, "\nReferences:")
+		for _, r := range g.References {
+			fmt.Fprintf(out, "  - %s\n", r)
+		}
+	}
+	return nil
+}
+
+// printSection prints a titled paragraph, skipping empty ones
+func printSection(title, body string) {
+	if strings.TrimSpace(body) == "" {
+		return
+	}
+	fmt.Fprintf(os.Stdout, "%s:\n  %s\n\n", title, strings.TrimSpace(body))
+}
+
+func fallbackStr(s, fb string) string {
+	if s == "" {
+		return fb
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	reportpkg "github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "history",
+		Short:   "Show the timeline of scans of one target from the output root's index.json",
+		Example: "yoro history --dir ./reports --target example.com",
+		RunE:    runHistory,
+	}
+
+	cmd.Flags().String("dir", "", "Output root holding index.json (default --output)")
+	cmd.Flags().String("target", "", "Target whose scans to list")
+	_ = viper.BindPFlag("history.dir", cmd.Flags().Lookup("dir"))
+	_ = viper.BindPFlag("history.target", cmd.Flags().Lookup("target"))
+	return cmd
+}
+
+func runHistory(_ *cobra.Command, _ []string) error {
+	raw := viper.GetString("history.target")
+	if raw == "" {
+		return usageErrorf("please provide --target")
+	}
+	target, err := utils.NormalizeTarget(raw)
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	dir := viper.GetString("history.dir")
+	if dir == "" {
+		dir = viper.GetString("output")
+	}
+
+	h, err := reportpkg.LoadHistory(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no %s in %s; it is written by each scan, so scan or import first", reportpkg.HistoryFile, dir)
+	}
+	if err != nil {
+		return err
+	}
+	entries := h.ForTarget(target)
+	if len(entries) == 0 {
+		return fmt.Errorf("no scans of %s in %s", target, dir)
+	}
+
+	sevs := []string{"critical", "high", "medium", "low", "info"}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCAN TIME\tSCORE\tGRADE\t"+strings.ToUpper(strings.Join(sevs, "\t"))+"\tTOTAL\tDIR")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%d\t%s", e.Timestamp.UTC().Format(time.RFC3339), e.Score, e.Grade)
+		for _, sev := range sevs {
+			fmt.Fprintf(w, "\t%d", e.Counts[sev])
+		}
+		fmt.Fprintf(w, "\t%d\t%s\n", e.Total, e.Dir)
+	}
+	return w.Flush()
+}
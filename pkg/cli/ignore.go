@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/viper"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/rules"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// loadIgnoreList reads --ignore-file, or ./.yoroignore when it exists; nil when there is none
+func loadIgnoreList() (*rules.IgnoreList, error) {
+	file := viper.GetString("ignore-file")
+	if file == "" {
+		if _, err := os.Stat(rules.DefaultIgnoreFile); errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		file = rules.DefaultIgnoreFile
+	}
+	return rules.LoadIgnoreFile(file)
+}
+
+// applyIgnoreList suppresses accepted findings and reports how many were matched
+func applyIgnoreList(list *rules.IgnoreList, findings []schema.Finding) {
+	if n := list.Apply(findings); n > 0 {
+		logf("🙈 %d finding(s) suppressed by %s\n", n, list.Path)
+	}
+}
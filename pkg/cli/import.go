@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	reportpkg "github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/scanners"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "import",
+		Short:   "Import results of an external scanner into a results.json for reporting",
+		Example: "yoro import --scanner nuclei --file nuclei.json --target https://example.com\n  yoro import --scanner openvas --file report.xml",
+		RunE:    runImport,
+	}
+
+	cmd.Flags().String("scanner", "", "Scanner that wrote --file: "+strings.Join(scanners.ImportFormats(), ", ")+" (nuclei JSON/JSONL, nmap -oX, OpenVAS/GVM XML, ...)")
+	cmd.Flags().String("format", "", "Same as --scanner")
+	cmd.Flags().String("file", "", "Report file to import")
+	cmd.Flags().String("target", "", "Target to file the findings under (required except for nmap/openvas, which default to one result per host)")
+	_ = cmd.Flags().MarkDeprecated("format", "use --scanner")
+	addLabelFlags(cmd, "import.env", "Environment of the imported scan, stored as label env", "Label stored in results.json as key=value (repeatable)")
+	_ = viper.BindPFlag("import.scanner", cmd.Flags().Lookup("scanner"))
+	_ = viper.BindPFlag("import.format", cmd.Flags().Lookup("format"))
+	_ = viper.BindPFlag("import.file", cmd.Flags().Lookup("file"))
+	_ = viper.BindPFlag("import.target", cmd.Flags().Lookup("target"))
+	return cmd
+}
+
+func runImport(cmd *cobra.Command, _ []string) error {
+	scanner := viper.GetString("import.scanner")
+	if scanner == "" {
+		scanner = viper.GetString("import.format")
+	}
+	scanner = strings.ToLower(strings.TrimSpace(scanner))
+	file := viper.GetString("import.file")
+	if scanner == "" || file == "" {
+		return usageErrorf("please provide --scanner and --file with the report to import")
+	}
+	if !slices.Contains(scanners.ImportFormats(), scanner) {
+		return usageErrorf("invalid --scanner %q (expected %s)", scanner, strings.Join(scanners.ImportFormats(), ", "))
+	}
+	target := strings.TrimSpace(viper.GetString("import.target"))
+	if target == "" && scanners.ImportNeedsTarget(scanner) {
+		return usageErrorf("please provide --target: %s reports do not name the scanned host", scanner)
+	}
+	// Stored like scanned targets, so history, trend, baselines and ignore rules match
+	if target != "" {
+		normalized, err := utils.NormalizeTarget(target)
+		if err != nil {
+			return withKind(ErrUsage, err)
+		}
+		target = normalized
+	}
+	labels, err := labelFlags(cmd, "import.env")
+	if err != nil {
+		return err
+	}
+	ignore, err := loadIgnoreList()
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	severities, err := loadSeverityMap()
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	weights, err := scoreWeights()
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+
+	findings, err := scanners.Import(scanner, file, target)
+	if err != nil {
+		return err
+	}
+	normalizeImportedTargets(findings)
+	scanners.AssignStableIDs(findings)
+
+	// Without --target every host in the report gets its own result
+	byTarget := map[string][]schema.Finding{}
+	var targets []string
+	if target != "" {
+		targets = []string{target}
+	}
+	for _, f := range findings {
+		if _, ok := byTarget[f.Target]; !ok && target == "" {
+			targets = append(targets, f.Target)
+		}
+		byTarget[f.Target] = append(byTarget[f.Target], f)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("%s contains no results; pass --target to record an empty scan", file)
+	}
+
+	now := time.Now()
+	for _, t := range targets {
+		found := byTarget[t]
+		applySeverityMap(severities, found)
+		applyIgnoreList(ignore, found)
+		res := schema.ScanResult{
+			Target:       t,
+			Labels:       labels,
+			Timestamp:    now,
+			ScannerMeta:  []schema.ScannerMeta{{Name: scanner, Command: "imported from " + file, Status: schema.ScannerOK}},
+			ScoreWeights: weights,
+			Findings:     found,
+		}
+		path, err := utils.SaveResult(res, viper.GetString("output"))
+		if err != nil {
+			return err
+		}
+		if viper.GetString("output") != utils.StdoutOutput {
+			if err := reportpkg.AppendHistory(viper.GetString("output"), filepath.Dir(path), res); err != nil {
+				return err
+			}
+		}
+		logf("📥 Imported %d finding(s) for %s: %s\n", len(found), t, path)
+	}
+	return nil
+}
+
+// normalizeImportedTargets normalizes the per-host targets of nmap and OpenVAS
+// reports the way --target is; a host that does not parse is kept as reported
+func normalizeImportedTargets(findings []schema.Finding) {
+	for i, f := range findings {
+		if t, err := utils.NormalizeTarget(f.Target); err == nil {
+			findings[i].Target = t
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	reportpkg "github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+const nmapReport = `<nmaprun>
+<host><address addr="10.0.0.5" addrtype="ipv4"/><ports>
+<port protocol="tcp" portid="22"><state state="open" reason="syn-ack"/><service name="ssh"/></port>
+</ports></host>
+<host><address addr="10.0.0.6" addrtype="ipv4"/><hostnames><hostname name="Intranet.Example.com"/></hostnames><ports>
+<port protocol="tcp" portid="80"><state state="open" reason="syn-ack"/></port>
+</ports></host>
+</nmaprun>`
+
+// runImportWith runs the import command on a report file written to a temp dir
+// and returns the targets of the results it saved
+func runImportWith(t *testing.T, scanner, report, target string) ([]string, error) {
+	t.Helper()
+	t.Chdir(t.TempDir()) // no .yoroignore from the working tree
+	file := filepath.Join(t.TempDir(), "report")
+	if err := os.WriteFile(file, []byte(report), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	cmd := newImportCmd()
+	for key, val := range map[string]string{"import.scanner": scanner, "import.file": file, "import.target": target, "output": out} {
+		viper.Set(key, val)
+		t.Cleanup(func() { viper.Set(key, nil) })
+	}
+	viper.Set("quiet", true)
+	t.Cleanup(func() { viper.Set("quiet", nil) })
+	if err := runImport(cmd, nil); err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(out, "*", "results.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var targets []string
+	for _, f := range files {
+		res, err := reportpkg.LoadScanResultFile(f)
+		if err != nil {
+			t.Fatalf("load %s: %v", f, err)
+		}
+		targets = append(targets, res.Target)
+	}
+	slices.Sort(targets)
+	return targets, nil
+}
+
+func TestImportNormalizesTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{"bare host", "Example.com", "https://example.com"},
+		{"default port", "https://example.com:443/", "https://example.com"},
+		{"explicit scheme", "http://intranet:8080", "http://intranet:8080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runImportWith(t, "nuclei", `{"template-id":"t1","info":{"severity":"high"}}`, tt.target)
+			if err != nil {
+				t.Fatalf("runImport: %v", err)
+			}
+			if !slices.Equal(got, []string{tt.want}) {
+				t.Errorf("saved targets %v, want [%s]", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportInvalidTargetIsUsageError(t *testing.T) {
+	_, err := runImportWith(t, "nuclei", `{"template-id":"t1"}`, "ftp://example.com")
+	if !errors.Is(err, ErrUsage) {
+		t.Errorf("got %v, want a usage error", err)
+	}
+}
+
+func TestImportNormalizesNmapHosts(t *testing.T) {
+	got, err := runImportWith(t, "nmap", nmapReport, "")
+	if err != nil {
+		t.Fatalf("runImport: %v", err)
+	}
+	want := []string{"https://10.0.0.5", "https://intranet.example.com"}
+	if !slices.Equal(got, want) {
+		t.Errorf("saved targets %v, want %v", got, want)
+	}
+}
+
+func TestNormalizeImportedTargets(t *testing.T) {
+	findings := []schema.Finding{
+		{Target: "10.0.0.5"},
+		{Target: "Scanner.Example.com"},
+		{Target: "https://example.com:443"},
+		{Target: "not a host"},
+	}
+	normalizeImportedTargets(findings)
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Target)
+	}
+	want := []string{"https://10.0.0.5", "https://scanner.example.com", "https://example.com", "not a host"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+// addLabelFlags registers --env and --label on cmd, binding --env to envKey
+func addLabelFlags(cmd *cobra.Command, envKey, envUsage, labelUsage string) {
+	cmd.Flags().String("env", "", envUsage)
+	cmd.Flags().StringArray("label", nil, labelUsage)
+	_ = viper.BindPFlag(envKey, cmd.Flags().Lookup("env"))
+}
+
+// labelFlags collects --env and --label into a label map; nil when neither is given
+func labelFlags(cmd *cobra.Command, envKey string) (map[string]string, error) {
+	// Read labels straight from the flag: viper would split values on commas
+	pairs, _ := cmd.Flags().GetStringArray("label")
+	labels, err := utils.ParseLabels(viper.GetString(envKey), pairs)
+	if err != nil {
+		return nil, withKind(ErrUsage, err)
+	}
+	return labels, nil
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/notify"
+	reportpkg "github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+// addNotifyFlags registers the issue tracker flags of the scan command
+func addNotifyFlags(cmd *cobra.Command) {
+	cmd.Flags().String("github-repo", "", "Open a GitHub issue (owner/name) per new critical/high finding template; with --baseline only findings not in it count")
+	cmd.Flags().String("github-token", "", "GitHub token for --github-repo (env YORO_GITHUB_TOKEN or GITHUB_TOKEN)")
+	_ = viper.BindPFlag("github.repo", cmd.Flags().Lookup("github-repo"))
+	_ = viper.BindPFlag("github.token", cmd.Flags().Lookup("github-token"))
+	_ = viper.BindEnv("github.token", "YORO_GITHUB_TOKEN", "GITHUB_TOKEN")
+
+	cmd.Flags().String("jira-url", "", "Open a Jira issue per new finding template in this Jira, e.g. https://acme.atlassian.net (token: YORO_JIRA_TOKEN)")
+	cmd.Flags().String("jira-project", "", "Jira project key for --jira-url, e.g. SEC")
+	cmd.Flags().String("jira-issue-type", "Bug", "Jira issue type for --jira-url")
+	cmd.Flags().String("jira-min-severity", "high", "Least severe new finding that gets a Jira issue")
+	cmd.Flags().Bool("jira-attach", false, "Render each scanned target's report PDF into its result directory and attach it to the new Jira issues for its findings (needs Chrome)")
+	_ = viper.BindPFlag("jira.url", cmd.Flags().Lookup("jira-url"))
+	_ = viper.BindPFlag("jira.project", cmd.Flags().Lookup("jira-project"))
+	_ = viper.BindPFlag("jira.issue-type", cmd.Flags().Lookup("jira-issue-type"))
+	_ = viper.BindPFlag("jira.min-severity", cmd.Flags().Lookup("jira-min-severity"))
+	_ = viper.BindPFlag("jira.attach", cmd.Flags().Lookup("jira-attach"))
+	// Jira Cloud authenticates with the account email and an API token, Server/Data
+	// Center with a personal access token alone
+	_ = viper.BindEnv("jira.user", "YORO_JIRA_USER")
+	_ = viper.BindEnv("jira.token", "YORO_JIRA_TOKEN")
+}
+
+// addEmailFlags registers the report email flags of the report command
+func addEmailFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("email-to", nil, "Email the report (PDF/HTML attached) with a summary to these addresses (comma-separated or repeatable)")
+	cmd.Flags().String("email-from", "", "Sender address of the report email (default --smtp-user)")
+	cmd.Flags().String("smtp-host", "", "SMTP server for --email-to")
+	cmd.Flags().Int("smtp-port", 587, "SMTP port; 465 uses implicit TLS, others STARTTLS when offered")
+	cmd.Flags().String("smtp-user", "", "SMTP login, e.g. the mailbox address (password: YORO_SMTP_PASSWORD)")
+	_ = viper.BindPFlag("email.to", cmd.Flags().Lookup("email-to"))
+	_ = viper.BindPFlag("email.from", cmd.Flags().Lookup("email-from"))
+	_ = viper.BindPFlag("smtp.host", cmd.Flags().Lookup("smtp-host"))
+	_ = viper.BindPFlag("smtp.port", cmd.Flags().Lookup("smtp-port"))
+	_ = viper.BindPFlag("smtp.user", cmd.Flags().Lookup("smtp-user"))
+	_ = viper.BindEnv("smtp.password", "YORO_SMTP_PASSWORD")
+}
+
+// smtpConfig collects the SMTP settings for --email-to
+func smtpConfig() notify.SMTPConfig {
+	return notify.SMTPConfig{
+		Host:     viper.GetString("smtp.host"),
+		Port:     viper.GetInt("smtp.port"),
+		User:     viper.GetString("smtp.user"),
+		Password: viper.GetString("smtp.password"),
+		From:     viper.GetString("email.from"),
+	}
+}
+
+// emailReport sends the report to --email-to; a failure is reported but does not
+// fail the command, the report files are already written
+func emailReport(res schema.ScanResult, artifacts map[string]string) {
+	to := viper.GetStringSlice("email.to")
+	if len(to) == 0 {
+		return
+	}
+	var files []string
+	for _, format := range []string{"pdf", "html"} {
+		if path := artifacts[format]; path != "" {
+			files = append(files, path)
+		}
+	}
+	if err := notify.SendReport(smtpConfig(), to, res, files); err != nil {
+		logf("⚠️  %v\n", err)
+		return
+	}
+	logf("📧 Report emailed to %s\n", strings.Join(to, ", "))
+}
+
+// jiraConfig collects the Jira settings; URL is empty when Jira is not used
+func jiraConfig() notify.JiraConfig {
+	return notify.JiraConfig{
+		URL:         viper.GetString("jira.url"),
+		Project:     viper.GetString("jira.project"),
+		User:        viper.GetString("jira.user"),
+		Token:       viper.GetString("jira.token"),
+		IssueType:   viper.GetString("jira.issue-type"),
+		MinSeverity: viper.GetString("jira.min-severity"),
+	}
+}
+
+// validateNotify checks the tracker settings before any scan starts
+func validateNotify() error {
+	if repo := viper.GetString("github.repo"); repo != "" {
+		if err := notify.ValidateGitHubRepo(repo); err != nil {
+			return err
+		}
+		if viper.GetString("github.token") == "" {
+			return errors.New("--github-repo needs a token: pass --github-token or set YORO_GITHUB_TOKEN")
+		}
+	}
+	if viper.GetBool("jira.attach") {
+		if viper.GetString("jira.url") == "" {
+			return errors.New("--jira-attach needs --jira-url")
+		}
+		if viper.GetString("output") == utils.StdoutOutput {
+			return errors.New("--jira-attach renders the report into the result directory; it is not used with -o -")
+		}
+	}
+	if cfg := jiraConfig(); cfg.URL != "" {
+		return notify.ValidateJira(cfg)
+	}
+	return nil
+}
+
+// issueReport renders the report PDF for the result saved in dir when --jira-attach
+// is set; empty when it is not or rendering failed, which is reported but leaves
+// the issues to be filed without it
+func issueReport(dir string) string {
+	if !viper.GetBool("jira.attach") {
+		return ""
+	}
+	_, artifacts, err := renderScanDir(dir, []string{"pdf"}, reportpkg.Options{}, nil, nil, func(htmlPath string) (string, error) {
+		return writePDF(htmlPath, reportpkg.PDFOptions{}), nil
+	})
+	if err != nil {
+		logf("⚠️  Failed to render the report for Jira: %v\n", err)
+		return ""
+	}
+	return artifacts["pdf"]
+}
+
+// fileIssues opens tracker issues for new findings; reports maps finding IDs to the
+// PDF attached to their Jira issue. Tracker failures are reported but do not fail
+// the scan.
+func fileIssues(ctx context.Context, findings []schema.Finding, reports map[string]string) {
+	if repo := viper.GetString("github.repo"); repo != "" && len(notify.Serious(findings)) > 0 {
+		if err := notify.CreateIssues(ctx, repo, viper.GetString("github.token"), findings); err != nil {
+			logf("⚠️  %v\n", err)
+		} else {
+			logf("🎫 New critical/high findings synced to GitHub issues in %s\n", repo)
+		}
+	}
+	if cfg := jiraConfig(); cfg.URL != "" && len(notify.AtLeast(findings, cfg.MinSeverity)) > 0 {
+		cfg.Attachments = reports
+		if err := notify.CreateJiraIssues(ctx, cfg, findings); err != nil {
+			logf("⚠️  %v\n", err)
+		} else {
+			logf("🎫 New findings synced to Jira project %s\n", cfg.Project)
+		}
+	}
+}
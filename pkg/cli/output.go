@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/viper"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+// statusOut is where decorative status lines go: stdout normally, stderr when
+// stdout carries machine-readable output, and nowhere with --quiet
+func statusOut() io.Writer {
+	switch {
+	case viper.GetBool("quiet"):
+		return io.Discard
+	case jsonStdout():
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// jsonStdout reports whether stdout is reserved for JSON (--stdout, --summary-json or -o -)
+func jsonStdout() bool {
+	return viper.GetBool("stdout") || viper.GetBool("summary-json") || viper.GetString("output") == utils.StdoutOutput
+}
+
+// logf prints a status line
+func logf(format string, args ...any) {
+	writef(statusOut(), format, args...)
+}
+
+// writef prints to w, in plain text when w does not take color (see colorEnabled)
+func writef(w io.Writer, format string, args ...any) {
+	s := fmt.Sprintf(format, args...)
+	if f, ok := w.(*os.File); ok && !colorEnabled(f) {
+		s = plainText(s)
+	}
+	_, _ = io.WriteString(w, s)
+}
+
+// colorEnabled reports whether output to f may use emoji and ANSI escapes: not
+// with --no-color or NO_COLOR (https://no-color.org), nor when f is no terminal
+func colorEnabled(f *os.File) bool {
+	if viper.GetBool("no-color") || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(f)
+}
+
+// statusWords are the plain-text stand-ins for the emoji that start status lines
+var statusWords = strings.NewReplacer(
+	"🚀", "[RUN]", "✅", "[OK]", "⚠️", "[WARN]", "❌", "[FAIL]", "⏭️", "[SKIP]",
+	"🔁", "[RETRY]", "🙈", "[IGNORED]", "🎚️", "[SEVERITY]", "🎛️", "[PROFILE]", "🆕", "[NEW]", "🟰", "[SAME]",
+	"📊", "[SUMMARY]", "📝", "[HTML]", "📄", "[PDF]", "📦", "[JSON]", "🧾", "[NDJSON]",
+	"🏷️", "[BADGE]", "🗂️", "[MANIFEST]", "🕶️", "[ANON]", "🎫", "[ISSUE]", "📈", "[METRICS]", "🗄️", "[DB]", "⚙️", "[CONFIG]", "📥", "[IMPORT]", "📧", "[EMAIL]", "🌐", "[SERVE]", "🧪", "[DRY-RUN]", "⏳", "[WAIT]", "🛑", "[STOP]", "⬇️", "[UPDATE]",
+)
+
+var (
+	ansiEscape   = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+	wordPadding  = regexp.MustCompile(`\] {2,}`)
+	emojiJoiners = regexp.MustCompile(`[\x{FE0F}\x{200D}]`)
+)
+
+// plainText swaps status emoji for words and drops ANSI escapes and other pictographs
+func plainText(s string) string {
+	s = ansiEscape.ReplaceAllString(statusWords.Replace(s), "")
+	s = emojiJoiners.ReplaceAllString(s, "")
+	s = strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII && unicode.Is(unicode.So, r) {
+			return -1
+		}
+		return r
+	}, s)
+	return wordPadding.ReplaceAllString(s, "] ")
+}
+
+// toolOut is where external scanners' console output goes; never stdout when
+// stdout is reserved for JSON
+func toolOut() io.Writer {
+	switch {
+	case viper.GetBool("quiet"):
+		return io.Discard
+	case jsonStdout():
+		return os.Stderr
+	}
+	return os.Stdout
+}
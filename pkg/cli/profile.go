@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// presetSetting is one scan flag a profile sets
+type presetSetting struct {
+	key   string
+	value any
+}
+
+// scanProfiles are the --profile-scan presets. They only change defaults, so any
+// flag, YORO_* variable or config value given explicitly still wins.
+var scanProfiles = map[string][]presetSetting{
+	// Quick look for the worst problems only
+	"light": {
+		{"scanners", []string{"nuclei"}},
+		{"nuclei-severity", []string{"critical", "high"}},
+		{"scanner-timeout", "15m"},
+	},
+	// The plain defaults
+	"standard": {},
+	// Every nuclei template, port discovery and the built-in TLS, header and
+	// technology checks, with room for slow targets. There is no nmap or testssl
+	// scanner: masscan finds the open ports and cert checks the TLS setup.
+	"deep": {
+		{"scanners", []string{"nuclei", "masscan", "cert", "headers", "fingerprint"}},
+		{"scanner-timeout", "3h"},
+		{"retries", 1},
+	},
+}
+
+// profileKeys are the settings shown as the effective configuration
+var profileKeys = []string{"scanners", "nuclei-severity", "scanner-timeout", "retries"}
+
+// applyScanProfile installs the --profile-scan preset as defaults and prints the
+// resulting configuration
+func applyScanProfile() error {
+	name := strings.ToLower(strings.TrimSpace(viper.GetString("profile-scan")))
+	if name == "" {
+		return nil
+	}
+	if targetType() == "image" {
+		return usageErrorf("--profile-scan presets are for url targets; image scans run trivy")
+	}
+	preset, ok := scanProfiles[name]
+	if !ok {
+		names := make([]string, 0, len(scanProfiles))
+		for n := range scanProfiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return usageErrorf("unknown --profile-scan %q (expected %s)", name, strings.Join(names, ", "))
+	}
+	for _, s := range preset {
+		viper.SetDefault(s.key, s.value)
+	}
+
+	logf("🎛️  Scan profile %s:\n", name)
+	for _, key := range profileKeys {
+		logf("   %-16s %s\n", key+":", profileValue(key))
+	}
+	return nil
+}
+
+func profileValue(key string) string {
+	switch v := viper.Get(key).(type) {
+	case []string, []any:
+		// Config files give lists as []any
+		list := viper.GetStringSlice(key)
+		if len(list) == 0 {
+			return "all"
+		}
+		return strings.Join(list, ",")
+	case nil:
+		return "default"
+	case string:
+		if key == "scanner-timeout" && v == "" {
+			return "default per scanner"
+		}
+		if key == "nuclei-severity" && v == "" {
+			return "all"
+		}
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
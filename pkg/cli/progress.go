@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/scanners"
+)
+
+// progressView redraws a single status line with elapsed time, nuclei progress
+// and running finding counts by severity
+type progressView struct {
+	mu      sync.Mutex
+	out     io.Writer
+	target  string
+	started time.Time
+	last    scanners.Progress
+	counts  map[string]int
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func newProgressView(out io.Writer, target string) *progressView {
+	return &progressView{out: out, target: target, started: time.Now(), counts: map[string]int{}}
+}
+
+// Update is a scanners.ProgressFunc
+func (p *progressView) Update(ev scanners.Progress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ev.Severity != "" {
+		p.counts[strings.ToLower(ev.Severity)]++
+		return
+	}
+	p.last = ev
+}
+
+// Start begins redrawing twice per second until Stop
+func (p *progressView) Start() {
+	p.done = make(chan struct{})
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		tick := time.NewTicker(500 * time.Millisecond)
+		defer tick.Stop()
+		for {
+			p.draw()
+			select {
+			case <-p.done:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop draws the final state and moves to a fresh line
+func (p *progressView) Stop() {
+	close(p.done)
+	p.wg.Wait()
+	p.draw()
+	fmt.Fprintln(p.out)
+}
+
+func (p *progressView) draw() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	elapsed := time.Since(p.started).Round(time.Second)
+	stats := "starting…"
+	if p.last.Total > 0 || p.last.Templates > 0 {
+		stats = fmt.Sprintf("templates %d · requests %d/%d (%d%%)", p.last.Templates, p.last.Requests, p.last.Total, p.last.Percent)
+	}
+	fmt.Fprintf(p.out, "\r\033[K⏱  %s  %s  │  %s  │ C:%d H:%d M:%d L:%d I:%d",
+		elapsed, p.target, stats,
+		p.counts["critical"], p.counts["high"], p.counts["medium"], p.counts["low"], p.counts["info"])
+}
@@ -4,70 +4,437 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/notify"
 	reportpkg "github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/rules"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
 )
 
 func newReportCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "report",
-		Short:   "Generate HTML/PDF report from a scan result directory",
-		Example: "yoro report --from ./reports/example.com_20250911_131722 --format html,pdf",
-		RunE:    runReport,
+		Use:   "report",
+		Short: "Generate HTML/PDF report from a scan result directory",
+		Example: `yoro report --from ./reports/example.com_20250911_131722 --format html,pdf
+yoro report --from-dir ./reports --all --concurrency 4`,
+		RunE: runReport,
 	}
 
 	cmd.Flags().String("from", "", "Scan result directory (must contain results.json)")
-	cmd.Flags().String("format", "html,pdf", "Output formats: html,pdf,json (json just points to results.json)")
+	cmd.Flags().String("from-dir", "", "Root directory of many scan result directories (with --aggregate or --all)")
+	cmd.Flags().Bool("aggregate", false, "Render one summary report for all scans under --from-dir (latest per target)")
+	cmd.Flags().Bool("all", false, "Render the reports of every scan directory under --from-dir, sharing one Chrome for the PDFs")
+	cmd.Flags().Int("concurrency", 4, "With --all, number of scan directories rendered in parallel")
+	cmd.Flags().String("since", "", "With --aggregate, ignore scans before this date (2006-01-02, RFC3339 or age such as 90d)")
+	cmd.Flags().String("until", "", "With --aggregate, ignore scans after this date (a date includes the whole day)")
+	cmd.Flags().String("format", "html,pdf", "Output formats: html,pdf,json,yaml,ndjson,badge (json just points to results.json; yaml writes results.yaml; ndjson writes findings.ndjson for SIEMs; badge writes a grade badge.svg)")
+	cmd.Flags().String("report-title", "", "Custom report title (default \"Security Report — <target>\")")
+	cmd.Flags().String("report-name", "", "Single-scan report file name without extension; placeholders {target}, {date}, {score}, {grade} (default \"report\")")
+	cmd.Flags().Bool("anonymize", false, "Replace target hosts in the HTML/PDF report with stable pseudonyms (target-<hash>); results.json keeps real values and anonymize-map.json records the mapping")
+	cmd.Flags().Bool("redact", false, "Mask secrets and PII (JWTs, bearer tokens, AWS keys, emails) in report descriptions and evidence")
+	cmd.Flags().StringArray("redact-pattern", nil, "Extra regex to mask with --redact (repeatable; config: report.redact-patterns)")
+	cmd.Flags().String("logo", "", "PNG/JPEG/SVG logo embedded in the report header")
+	cmd.Flags().String("theme", "", "YAML file overriding severity colors/labels (config: report.theme)")
+	cmd.Flags().String("lang", reportpkg.DefaultLang, "Language of the report's labels: "+strings.Join(reportpkg.Languages(), "|"))
+	cmd.Flags().String("chrome-path", "", "Chrome/Chromium binary used for PDF output (default: found in PATH)")
+	cmd.Flags().Bool("chrome-no-sandbox", false, "Run Chrome without its sandbox, needed as root in containers; weakens isolation, so only for trusted reports")
+	cmd.Flags().String("chrome-remote", "", "DevTools URL of a running Chrome to reuse for PDF output, e.g. ws://127.0.0.1:9222 (chrome --remote-debugging-port=9222)")
+	cmd.Flags().StringArray("chrome-flag", nil, "Extra Chrome switch for PDF output, e.g. disable-dev-shm-usage or window-size=1280,800 (repeatable)")
 
+	addEmailFlags(cmd)
+	addLabelFlags(cmd, "report.env", "With --aggregate, only include scans with this env label", "With --aggregate, only include scans with this key=value label (repeatable)")
 	_ = viper.BindPFlag("report.from", cmd.Flags().Lookup("from"))
+	_ = viper.BindPFlag("report.from-dir", cmd.Flags().Lookup("from-dir"))
+	_ = viper.BindPFlag("report.aggregate", cmd.Flags().Lookup("aggregate"))
+	_ = viper.BindPFlag("report.all", cmd.Flags().Lookup("all"))
+	_ = viper.BindPFlag("report.concurrency", cmd.Flags().Lookup("concurrency"))
+	_ = viper.BindPFlag("report.since", cmd.Flags().Lookup("since"))
+	_ = viper.BindPFlag("report.until", cmd.Flags().Lookup("until"))
 	_ = viper.BindPFlag("report.format", cmd.Flags().Lookup("format"))
+	_ = viper.BindPFlag("report.title", cmd.Flags().Lookup("report-title"))
+	_ = viper.BindPFlag("report.name", cmd.Flags().Lookup("report-name"))
+	_ = viper.BindPFlag("report.redact", cmd.Flags().Lookup("redact"))
+	_ = viper.BindPFlag("report.anonymize", cmd.Flags().Lookup("anonymize"))
+	_ = viper.BindPFlag("report.logo", cmd.Flags().Lookup("logo"))
+	_ = viper.BindPFlag("report.theme", cmd.Flags().Lookup("theme"))
+	_ = viper.BindPFlag("report.lang", cmd.Flags().Lookup("lang"))
+	_ = viper.BindPFlag("report.chrome-path", cmd.Flags().Lookup("chrome-path"))
+	_ = viper.BindPFlag("report.chrome-no-sandbox", cmd.Flags().Lookup("chrome-no-sandbox"))
+	_ = viper.BindPFlag("report.chrome-remote", cmd.Flags().Lookup("chrome-remote"))
 	return cmd
 }
 
 func runReport(cmd *cobra.Command, _ []string) error {
+	formats := strings.Split(viper.GetString("report.format"), ",")
+	for i := range formats {
+		formats[i] = strings.TrimSpace(strings.ToLower(formats[i]))
+	}
+
+	opts := reportpkg.Options{
+		Title:    viper.GetString("report.title"),
+		LogoPath: viper.GetString("report.logo"),
+		Lang:     viper.GetString("report.lang"),
+	}
+	if err := reportpkg.ValidateLang(opts.Lang); err != nil {
+		return withKind(ErrUsage, err)
+	}
+	var err error
+	if path := viper.GetString("report.theme"); path != "" {
+		if opts.Theme, err = reportpkg.LoadTheme(path); err != nil {
+			return err
+		}
+	}
+
+	if viper.GetBool("report.redact") {
+		// Read patterns straight from the flag: viper would split them on commas
+		extra, _ := cmd.Flags().GetStringArray("redact-pattern")
+		patterns := slices.Concat(reportpkg.DefaultRedactPatterns, viper.GetStringSlice("report.redact-patterns"), extra)
+		if opts.Redact, err = reportpkg.CompileRedactions(patterns); err != nil {
+			return err
+		}
+	}
+
+	ignore, err := loadIgnoreList()
+	if err != nil {
+		return err
+	}
+	severities, err := loadSeverityMap()
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+
+	pdf := pdfOptions(cmd)
+	pdf.Lang = opts.Lang
+	window, err := utils.ParseTimeRange(viper.GetString("report.since"), viper.GetString("report.until"), time.Now())
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	labels, err := labelFlags(cmd, "report.env")
+	if err != nil {
+		return err
+	}
+	if len(viper.GetStringSlice("email.to")) > 0 && (viper.GetBool("report.aggregate") || viper.GetBool("report.all")) {
+		return usageErrorf("--email-to sends a single --from report; it is not used with --aggregate or --all")
+	}
+	if viper.GetBool("report.aggregate") && viper.GetBool("report.all") {
+		return usageErrorf("--aggregate renders one summary and --all one report per scan; use one of them")
+	}
+	if viper.GetBool("report.aggregate") {
+		filter := scanFilter{window: window, labels: labels}
+		return runAggregateReport(viper.GetString("report.from-dir"), formats, opts, pdf, severities, ignore, filter)
+	}
+	if window != (utils.TimeRange{}) || labels != nil {
+		return usageErrorf("--since, --until, --env and --label are only used with --aggregate")
+	}
+	if viper.GetBool("report.all") {
+		return runBatchReport(viper.GetString("report.from-dir"), formats, opts, pdf, severities, ignore)
+	}
+	if viper.GetString("report.from-dir") != "" {
+		return errors.New("--from-dir is only used with --aggregate or --all (use --from for a single scan)")
+	}
+
 	from := viper.GetString("report.from")
 	if from == "" {
 		return errors.New("please provide --from pointing to the scan directory (with results.json)")
 	}
-
-	formats := strings.Split(viper.GetString("report.format"), ",")
-	for i := range formats {
-		formats[i] = strings.TrimSpace(strings.ToLower(formats[i]))
+	to := viper.GetStringSlice("email.to")
+	if len(to) > 0 {
+		if err := notify.ValidateSMTP(smtpConfig(), to); err != nil {
+			return withKind(ErrUsage, err)
+		}
+	}
+	view, artifacts, err := renderScanDir(from, formats, opts, severities, ignore, func(htmlPath string) (string, error) {
+		return writePDF(htmlPath, pdf), nil
+	})
+	if err != nil {
+		return err
 	}
+	emailReport(view, artifacts)
+	return nil
+}
 
+// renderScanDir writes the requested formats for the scan in from and returns the
+// result as rendered (anonymized with --anonymize) and the files written; pdf
+// renders the HTML report, and its error fails the directory
+func renderScanDir(from string, formats []string, opts reportpkg.Options, severities *rules.SeverityMap, ignore *rules.IgnoreList, pdf func(htmlPath string) (string, error)) (schema.ScanResult, map[string]string, error) {
 	// Load scan results and render HTML
 	res, err := reportpkg.LoadScanResult(from)
 	if err != nil {
-		return err
+		return schema.ScanResult{}, nil, err
+	}
+	applySeverityMap(severities, res.Findings)
+	applyIgnoreList(ignore, res.Findings)
+	// The rendered report may be anonymized; the data exports below keep real values
+	view, anon := res, anonymizer()
+	if anon != nil {
+		view = anon.Result(res)
 	}
-	htmlPath, err := reportpkg.GenerateHTML(res, from)
+	if pattern := viper.GetString("report.name"); pattern != "" {
+		if opts.Name = reportpkg.ExpandReportName(pattern, view); opts.Name == "" {
+			return schema.ScanResult{}, nil, errors.New("--report-name expands to an empty file name")
+		}
+	}
+	htmlPath, err := reportpkg.GenerateHTML(view, from, opts)
 	if err != nil {
-		return err
+		return schema.ScanResult{}, nil, err
+	}
+	logf("📝 HTML report: %s\n", htmlPath)
+	artifacts := map[string]string{"html": htmlPath}
+	if err := writeAnonymizeMap(anon, from); err != nil {
+		return schema.ScanResult{}, nil, err
 	}
-	fmt.Printf("📝 HTML report: %s\n", htmlPath)
 
 	// Optional PDF (Chromedp-based)
 	if contains(formats, "pdf") {
-		pdfPath, err := reportpkg.GeneratePDF(htmlPath)
+		path, err := pdf(htmlPath)
 		if err != nil {
-			fmt.Printf("⚠️  PDF generation failed: %v\n", err)
-		} else {
-			fmt.Printf("📄 PDF report:  %s\n", pdfPath)
+			return schema.ScanResult{}, nil, err
+		}
+		if path != "" {
+			artifacts["pdf"] = path
 		}
 	}
 
 	// Optional JSON passthrough
 	if contains(formats, "json") {
-		fmt.Printf("📦 JSON already exists at: %s\n", filepath.Join(from, "results.json"))
+		path := filepath.Join(from, "results.json")
+		logf("📦 JSON already exists at: %s\n", path)
+		artifacts["json"] = path
+	}
+
+	// Optional YAML copy of the results for YAML-centric pipelines
+	if contains(formats, "yaml") {
+		path, err := utils.SaveResultYAML(res, from)
+		if err != nil {
+			return schema.ScanResult{}, nil, err
+		}
+		logf("📦 YAML results: %s\n", path)
+		artifacts["yaml"] = path
+	}
+
+	// Optional NDJSON export, one finding per line
+	if contains(formats, "ndjson") {
+		path, err := reportpkg.GenerateNDJSON(res, from)
+		if err != nil {
+			return schema.ScanResult{}, nil, err
+		}
+		logf("🧾 NDJSON findings: %s\n", path)
+		artifacts["ndjson"] = path
+	}
+
+	// Optional grade badge for dashboards and READMEs
+	if contains(formats, "badge") {
+		path, err := reportpkg.GenerateBadge(res, from)
+		if err != nil {
+			return schema.ScanResult{}, nil, err
+		}
+		logf("🏷️  Badge: %s\n", path)
+		artifacts["badge"] = path
+	}
+
+	return view, artifacts, writeManifest(from, artifacts)
+}
+
+// runAggregateReport renders one roll-up report for every scan under root
+func runAggregateReport(root string, formats []string, opts reportpkg.Options, pdf reportpkg.PDFOptions, severities *rules.SeverityMap, ignore *rules.IgnoreList, filter scanFilter) error {
+	if root == "" {
+		return errors.New("please provide --from-dir with the root of the scan result directories")
+	}
+	dirs, err := reportpkg.FindResultDirs(root)
+	if err != nil {
+		return err
 	}
+	var results []schema.ScanResult
+	anon := anonymizer()
+	for _, dir := range dirs {
+		res, err := reportpkg.LoadScanResult(dir)
+		if err != nil {
+			logf("⚠️  Skipping %s: %v\n", dir, err)
+			continue
+		}
+		if !filter.match(res) {
+			continue
+		}
+		applySeverityMap(severities, res.Findings)
+		applyIgnoreList(ignore, res.Findings)
+		if anon != nil {
+			res = anon.Result(res)
+		}
+		results = append(results, res)
+	}
+	if len(results) == 0 {
+		if filter.window != (utils.TimeRange{}) || filter.labels != nil {
+			return fmt.Errorf("no scans under %s match --since/--until/--env/--label", root)
+		}
+		return fmt.Errorf("no results.json found under %s", root)
+	}
+
+	htmlPath, err := reportpkg.GenerateAggregateHTML(results, root, opts)
+	if err != nil {
+		return err
+	}
+	if err := writeAnonymizeMap(anon, root); err != nil {
+		return err
+	}
+	logf("📝 Aggregate HTML report (%d scans): %s\n", len(results), htmlPath)
+	artifacts := map[string]string{"html": htmlPath}
+	if contains(formats, "pdf") {
+		if path := writePDF(htmlPath, pdf); path != "" {
+			artifacts["pdf"] = path
+		}
+	}
+	return writeManifest(root, artifacts)
+}
+
+// runBatchReport renders every scan directory under root like a single --from
+// report, a few at a time, and lists which ones failed
+func runBatchReport(root string, formats []string, opts reportpkg.Options, pdf reportpkg.PDFOptions, severities *rules.SeverityMap, ignore *rules.IgnoreList) error {
+	if root == "" {
+		return usageErrorf("please provide --from-dir with the root of the scan result directories")
+	}
+	concurrency := viper.GetInt("report.concurrency")
+	if concurrency < 1 {
+		return usageErrorf("--concurrency must be at least 1")
+	}
+	dirs, err := reportpkg.FindResultDirs(root)
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("no results.json found under %s", root)
+	}
+
+	// One browser serves every PDF; starting Chrome per report is what makes bulk runs slow
+	renderPDF := func(string) (string, error) { return "", nil }
+	if contains(formats, "pdf") {
+		if pdf.NoSandbox && pdf.RemoteURL == "" {
+			logf("⚠️  Chrome sandbox disabled (--chrome-no-sandbox); only render reports you trust\n")
+		}
+		renderer, err := reportpkg.NewPDFRenderer(pdf)
+		if err != nil {
+			return err
+		}
+		defer renderer.Close()
+		renderPDF = func(htmlPath string) (string, error) {
+			path, err := renderer.Render(htmlPath)
+			if err != nil {
+				return "", err
+			}
+			logf("📄 PDF report:  %s\n", path)
+			return path, nil
+		}
+	}
+
+	errs := make([]error, len(dirs))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, dir := range dirs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			_, _, errs[i] = renderScanDir(dir, formats, opts, severities, ignore, renderPDF)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	logf("📊 Rendered %d of %d scan directories under %s\n", len(dirs)-failed, len(dirs), root)
+	for i, dir := range dirs {
+		if errs[i] != nil {
+			logf("   ❌ %s: %v\n", dir, errs[i])
+		} else {
+			logf("   ✅ %s\n", dir)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d scan directories failed", failed, len(dirs))
+	}
+	return nil
+}
+
+// anonymizer returns the host anonymizer for --anonymize, nil without it
+func anonymizer() *reportpkg.Anonymizer {
+	if !viper.GetBool("report.anonymize") {
+		return nil
+	}
+	return reportpkg.NewAnonymizer()
+}
+
+// writeAnonymizeMap saves which pseudonym stands for which host, for the owners only
+func writeAnonymizeMap(anon *reportpkg.Anonymizer, dir string) error {
+	if anon == nil {
+		return nil
+	}
+	path, err := anon.WriteMapping(dir)
+	if err != nil {
+		return err
+	}
+	logf("🕶️  Anonymized %d host(s); mapping kept in %s (do not share it)\n", len(anon.Mapping()), path)
+	return nil
+}
 
+// writeManifest records the generated files in dir's manifest.json
+func writeManifest(dir string, artifacts map[string]string) error {
+	path, err := reportpkg.UpdateManifest(dir, artifacts)
+	if err != nil {
+		return err
+	}
+	logf("🗂️  Manifest: %s\n", path)
 	return nil
 }
 
+// pdfOptions collects the Chrome settings for PDF output
+func pdfOptions(cmd *cobra.Command) reportpkg.PDFOptions {
+	// Read flags straight from the flag: viper would split values such as window-size=1280,800
+	flags, _ := cmd.Flags().GetStringArray("chrome-flag")
+	return reportpkg.PDFOptions{
+		ChromePath: viper.GetString("report.chrome-path"),
+		NoSandbox:  viper.GetBool("report.chrome-no-sandbox"),
+		Flags:      slices.Concat(viper.GetStringSlice("report.chrome-flags"), flags),
+		RemoteURL:  viper.GetString("report.chrome-remote"),
+	}
+}
+
+// scanFilter selects which stored scans a multi-scan command includes
+type scanFilter struct {
+	window utils.TimeRange
+	labels map[string]string
+}
+
+func (f scanFilter) match(res schema.ScanResult) bool {
+	return f.window.Contains(res.Timestamp) && res.HasLabels(f.labels)
+}
+
+// writePDF renders htmlPath to PDF and returns its path; failures are reported
+// but not fatal and return ""
+func writePDF(htmlPath string, pdf reportpkg.PDFOptions) string {
+	if pdf.NoSandbox && pdf.RemoteURL == "" {
+		logf("⚠️  Chrome sandbox disabled (--chrome-no-sandbox); only render reports you trust\n")
+	}
+	pdfPath, err := reportpkg.GeneratePDF(htmlPath, pdf)
+	if err != nil {
+		logf("⚠️  PDF generation failed: %v\n", err)
+		return ""
+	}
+	logf("📄 PDF report:  %s\n", pdfPath)
+	return pdfPath
+}
+
 func contains(arr []string, v string) bool {
 	for _, x := range arr {
 		if x == v {
@@ -1,8 +1,11 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -12,18 +15,51 @@ import (
 var (
 	Version = "0.0.1"
 	rootCmd *cobra.Command
+
+	// commandCtx is the running command's context once --timeout has applied its
+	// deadline; stopTimeout releases the deadline's timer
+	commandCtx  context.Context
+	stopTimeout context.CancelFunc = func() {}
 )
 
 func init() {
 	rootCmd = &cobra.Command{
 		Use:   "yoro",
 		Short: "SME self-service security agent",
-		Long:  "Yorozuya SME security agent: run baseline scans, generate reports, and integrate with developer workflows.",
+		Long: `Yorozuya SME security agent: run baseline scans, generate reports, and integrate with developer workflows.
+
+Exit codes:
+  0  success
+  1  usage error (invalid flags, arguments or configuration)
+  2  scanner error (missing binary or failed scan)
+  3  findings at or above the --fail-on severity
+  4  stopped by --timeout (results completed before then are saved)`,
+		SilenceErrors:     true,
+		SilenceUsage:      true,
+		PersistentPreRunE: applyTimeout,
 	}
 
+	cobra.OnInitialize(initConfig)
+
 	// Global flags
-	rootCmd.PersistentFlags().StringP("output", "o", "./reports", "Output directory")
+	rootCmd.PersistentFlags().String("config", "", "Config file (YAML/JSON/TOML) with defaults such as scope lists; merged over /etc/yoro/config.yaml, $XDG_CONFIG_HOME/yoro/config.yaml and ./yoro.yaml, which are read when present")
+	rootCmd.PersistentFlags().StringP("output", "o", "./reports", "Output directory, or - to write results JSON to stdout (status to stderr; nothing is saved)")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress status output and scanner console output")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Verbose output (secrets are redacted)")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Plain text output without emoji or ANSI escapes (also NO_COLOR; automatic when not on a terminal)")
+	rootCmd.PersistentFlags().String("ignore-file", "", "File of accepted findings to suppress (default ./.yoroignore when present)")
+	rootCmd.PersistentFlags().String("severity-map", "", "YAML file of template/tag severity overrides applied before scoring and reporting")
+	rootCmd.PersistentFlags().Bool("offline", false, "Air-gapped mode: never download scanner updates")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Hard limit on the whole command, e.g. 2h; on expiry running scanners are stopped and finished results saved (0 disables)")
+	_ = viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
 	_ = viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	_ = viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
+	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	_ = viper.BindPFlag("no-color", rootCmd.PersistentFlags().Lookup("no-color"))
+	_ = viper.BindPFlag("ignore-file", rootCmd.PersistentFlags().Lookup("ignore-file"))
+	_ = viper.BindPFlag("severity-map", rootCmd.PersistentFlags().Lookup("severity-map"))
+	_ = viper.BindPFlag("offline", rootCmd.PersistentFlags().Lookup("offline"))
+	_ = viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
 
 	// Environment variable support (YORO_OUTPUT, etc.)
 	viper.SetEnvPrefix("YORO")
@@ -33,12 +69,90 @@ func init() {
 	// Subcommands
 	rootCmd.AddCommand(newScanCmd())
 	rootCmd.AddCommand(newReportCmd())
+	rootCmd.AddCommand(newImportCmd())
+	rootCmd.AddCommand(newScannersCmd())
+	rootCmd.AddCommand(newExplainCmd())
+	rootCmd.AddCommand(newValidateCmd())
+	rootCmd.AddCommand(newTrendCmd())
+	rootCmd.AddCommand(newHistoryCmd())
+	rootCmd.AddCommand(newDBCmd())
+	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newUpdateCmd())
 	rootCmd.AddCommand(newVersionCmd())
 }
 
+// initConfig merges the config files that exist, most general first, so each
+// overrides the ones before it: the org-wide defaults, the user's, ./yoro.yaml,
+// then --config (or YORO_CONFIG)
+func initConfig() {
+	files := configFiles()
+	if cfg := viper.GetString("config"); cfg != "" {
+		files = append(files, cfg)
+	}
+	for _, file := range files {
+		viper.SetConfigFile(file)
+		if err := viper.MergeInConfig(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read config %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		if viper.GetBool("verbose") {
+			logf("⚙️  Loaded config %s\n", file)
+		}
+	}
+}
+
+// configFiles returns the default config locations that exist, most general first
+func configFiles() []string {
+	userDir := os.Getenv("XDG_CONFIG_HOME")
+	if userDir == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			userDir = filepath.Join(home, ".config")
+		}
+	}
+	candidates := []string{"/etc/yoro/config.yaml"}
+	if userDir != "" {
+		candidates = append(candidates, filepath.Join(userDir, "yoro", "config.yaml"))
+	}
+	candidates = append(candidates, "yoro.yaml")
+
+	var files []string
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			files = append(files, c)
+		}
+	}
+	return files
+}
+
+// applyTimeout gives the command a --timeout deadline; everything it starts
+// shares that context, so expiry cancels running scanners
+func applyTimeout(cmd *cobra.Command, _ []string) error {
+	limit := viper.GetDuration("timeout")
+	if limit < 0 {
+		return usageErrorf("--timeout must not be negative")
+	}
+	if limit > 0 {
+		ctx, cancel := context.WithTimeout(cmd.Context(), limit)
+		cmd.SetContext(ctx)
+		stopTimeout = cancel
+	}
+	commandCtx = cmd.Context()
+	return nil
+}
+
+// timedOut reports whether ctx ended because the --timeout deadline passed
+func timedOut(ctx context.Context) bool {
+	return viper.GetDuration("timeout") > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	err := rootCmd.Execute()
+	stopTimeout()
+	if err != nil && commandCtx != nil && timedOut(commandCtx) {
+		err = &exitError{kind: ErrTimeout, err: fmt.Errorf("stopped after --timeout %s: %w", viper.GetDuration("timeout"), err)}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitCode(err))
 	}
 }
@@ -1,13 +1,27 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	reportpkg "github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/rules"
 	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/scanners"
 	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
 	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
@@ -17,44 +31,1040 @@ func newScanCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "scan",
 		Short: "Run a baseline security scan (skeleton)",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			target := viper.GetString("target")
-			if target == "" {
-				return errors.New("please provide --target")
+		RunE:  runScan,
+	}
+
+	cmd.Flags().String("target", "", "Target to scan (URL, host[:port], IP or CIDR range such as 10.0.0.0/24), or - to read one per line from stdin")
+	cmd.Flags().String("scheme", "", "Scheme for targets given without one: http or https (default https; a scheme in the target wins)")
+	cmd.Flags().String("targets-file", "", "File with one target per line ('#' comments and blank lines are ignored)")
+	cmd.Flags().String("url-list", "", "File of endpoint URLs (one per line) to scan with nuclei -list; other scanners scan each URL's host")
+	cmd.Flags().String("attest", "", "Authorization statement (e.g., 'I am authorized to test this target')")
+	cmd.Flags().String("target-type", "url", "Kind of target: url (web apps/hosts) or image (container images, scanned with trivy)")
+	cmd.Flags().StringSlice("scanners", []string{"nuclei"}, "Scanners to run (see `yoro scanners`), e.g. nuclei,zap")
+	cmd.Flags().String("profile-scan", "", "Preset: light (nuclei critical/high only, 15m limit), standard (the defaults) or deep (all nuclei templates, masscan ports in place of nmap, cert in place of testssl, headers and fingerprint checks, 3h limit, 1 retry); explicit flags still win")
+	cmd.Flags().Bool("stdout", false, "Also write the results JSON to stdout (status lines and tool output go to stderr)")
+	cmd.Flags().Bool("summary-json", false, "Print a one-line JSON summary per target (score, grade, risk_score, counts by severity, total) to stdout at the end")
+	cmd.Flags().Bool("progress", false, "Show a live progress line (elapsed time, templates, findings by severity) on a terminal")
+	cmd.Flags().Int("concurrency", 1, "Number of targets to scan in parallel")
+	cmd.Flags().Bool("confirm", false, "Allow expanding CIDR ranges larger than /16")
+	cmd.Flags().Bool("expand-subdomains", false, "Discover subdomains of each domain target with subfinder and scan the in-scope ones too")
+	cmd.Flags().Int("retries", 0, "Re-run a scanner up to N times when it exits with an error (usage and config errors are not retried)")
+	cmd.Flags().Duration("retry-backoff", 5*time.Second, "Wait before the first retry; doubles on each further attempt")
+	cmd.Flags().String("scanner-timeout", "", "Time limit per scanner run, e.g. 30m or nuclei=300s,masscan=120s (0 disables; default per scanner)")
+	cmd.Flags().Bool("update-templates", false, "Update nuclei templates before scanning (at most once per update.max-age; skipped with --offline)")
+	cmd.Flags().String("baseline", "", "Approved results.json; --fail-on then only counts findings not in it")
+	cmd.Flags().String("fail-on", "", "Exit with code 3 when a finding of this severity or higher is reported (critical, high, medium, low, info)")
+	cmd.Flags().Bool("templates-only", false, "Ask nuclei (-tl) which templates the tag/ID/workflow selection matches, print their count and IDs, and exit without scanning")
+	cmd.Flags().Bool("dry-run", false, "Print the scan plan (targets, scanners, command lines, output) without scanning")
+	cmd.Flags().StringSlice("exclude-severity", nil, "Drop findings of these severities before saving, e.g. info,low: unlike the report's severity filter, which only hides them, they never reach results.json and cannot be recovered, and the score counts only what is kept")
+	cmd.Flags().Int("max-findings", 0, "Keep at most N findings per target, most severe first, and mark the result truncated (0 for no limit)")
+	cmd.Flags().Bool("include-raw", false, "Embed each finding's original nuclei record in results.json (\"raw\")")
+	cmd.Flags().Bool("keep-raw", false, "Save each scanner's raw report next to results.json (e.g. nuclei.raw.json) for debugging")
+	cmd.Flags().Bool("split-by-scanner", false, "Also save results.<scanner>.json per scanner (e.g. results.nuclei.json), each with only that scanner's findings")
+	cmd.Flags().Duration("interval", 0, "Re-scan every interval (e.g. 6h) until interrupted, each run in its own directory, listing findings new since the previous run")
+	cmd.Flags().String("metrics-file", "", "Write Prometheus metrics (findings by severity, score, duration) here after the scan, e.g. for the node_exporter textfile collector")
+	cmd.Flags().Bool("resume", false, "Skip targets that already have a complete results.json in the output directory")
+	cmd.Flags().StringSlice("scope-allow", nil, "Allowed scope: domains, *.wildcard domains, IPs or CIDRs (config: scope.allow)")
+	cmd.Flags().StringSlice("scope-deny", nil, "Denied scope, checked before the allowlist (config: scope.deny)")
+	addLabelFlags(cmd, "env",
+		"Environment of the target, e.g. prod or staging; stored as label env and added to the result directory name",
+		"Label stored in results.json as key=value, e.g. team=web (repeatable)")
+	cmd.Flags().String("operator", "", "Person running the scan, recorded in the audit trail (default $USER)")
+	cmd.Flags().String("ca-file", "", "PEM bundle of extra CAs the built-in headers check trusts, for targets with internal-CA certificates")
+	cmd.Flags().Bool("insecure-skip-verify", false, "Let the built-in headers check accept any TLS certificate, e.g. self-signed ones (the cert scanner reports on them either way)")
+	cmd.Flags().String("proxy", "", "HTTP/SOCKS proxy for scanner traffic (e.g., http://proxy:8080, socks5://127.0.0.1:1080)")
+	cmd.Flags().StringArray("header", nil, "Extra HTTP header for authenticated scans, \"Name: value\" (repeatable)")
+	cmd.Flags().String("ports", scanners.DefaultMasscanPorts, "Ports for masscan, e.g. 80,443,8000-8100 or 0-65535")
+	cmd.Flags().StringSlice("include-tags", nil, "Nuclei template tags to run even if excluded by default (-include-tags)")
+	cmd.Flags().StringSlice("exclude-tags", nil, "Nuclei template tags to skip (-exclude-tags)")
+	cmd.Flags().StringSlice("exclude-template-id", nil, "Nuclei template IDs to skip, e.g. known false positives (-exclude-id; repeatable)")
+	addNotifyFlags(cmd)
+	cmd.Flags().StringSlice("nuclei-severity", nil, "Only run nuclei templates of these severities, e.g. critical,high (-severity)")
+	cmd.Flags().String("nuclei-workflow", "", "Nuclei workflow file to run (-w); the workflow picks the templates, so it may override the default template selection")
+	cmd.Flags().String("nuclei-cookie", "", "Session cookie sent with every nuclei request (e.g., 'session=abc123')")
+	_ = viper.BindPFlag("target", cmd.Flags().Lookup("target"))
+	_ = viper.BindPFlag("scheme", cmd.Flags().Lookup("scheme"))
+	_ = viper.BindPFlag("targets-file", cmd.Flags().Lookup("targets-file"))
+	_ = viper.BindPFlag("url-list", cmd.Flags().Lookup("url-list"))
+	_ = viper.BindPFlag("attest", cmd.Flags().Lookup("attest"))
+	_ = viper.BindPFlag("target-type", cmd.Flags().Lookup("target-type"))
+	_ = viper.BindPFlag("scanners", cmd.Flags().Lookup("scanners"))
+	_ = viper.BindPFlag("profile-scan", cmd.Flags().Lookup("profile-scan"))
+	_ = viper.BindPFlag("stdout", cmd.Flags().Lookup("stdout"))
+	_ = viper.BindPFlag("summary-json", cmd.Flags().Lookup("summary-json"))
+	_ = viper.BindPFlag("progress", cmd.Flags().Lookup("progress"))
+	_ = viper.BindPFlag("concurrency", cmd.Flags().Lookup("concurrency"))
+	_ = viper.BindPFlag("confirm", cmd.Flags().Lookup("confirm"))
+	_ = viper.BindPFlag("expand-subdomains", cmd.Flags().Lookup("expand-subdomains"))
+	_ = viper.BindPFlag("retries", cmd.Flags().Lookup("retries"))
+	_ = viper.BindPFlag("retry-backoff", cmd.Flags().Lookup("retry-backoff"))
+	_ = viper.BindPFlag("scanner-timeout", cmd.Flags().Lookup("scanner-timeout"))
+	_ = viper.BindPFlag("update-templates", cmd.Flags().Lookup("update-templates"))
+	_ = viper.BindPFlag("baseline", cmd.Flags().Lookup("baseline"))
+	_ = viper.BindPFlag("fail-on", cmd.Flags().Lookup("fail-on"))
+	_ = viper.BindPFlag("exclude-severity", cmd.Flags().Lookup("exclude-severity"))
+	_ = viper.BindPFlag("dry-run", cmd.Flags().Lookup("dry-run"))
+	_ = viper.BindPFlag("templates-only", cmd.Flags().Lookup("templates-only"))
+	_ = viper.BindPFlag("max-findings", cmd.Flags().Lookup("max-findings"))
+	_ = viper.BindPFlag("include-raw", cmd.Flags().Lookup("include-raw"))
+	_ = viper.BindPFlag("keep-raw", cmd.Flags().Lookup("keep-raw"))
+	_ = viper.BindPFlag("split-by-scanner", cmd.Flags().Lookup("split-by-scanner"))
+	_ = viper.BindPFlag("metrics-file", cmd.Flags().Lookup("metrics-file"))
+	_ = viper.BindPFlag("interval", cmd.Flags().Lookup("interval"))
+	_ = viper.BindPFlag("resume", cmd.Flags().Lookup("resume"))
+	_ = viper.BindPFlag("scope.allow", cmd.Flags().Lookup("scope-allow"))
+	_ = viper.BindPFlag("scope.deny", cmd.Flags().Lookup("scope-deny"))
+	_ = viper.BindPFlag("operator", cmd.Flags().Lookup("operator"))
+	_ = viper.BindPFlag("proxy", cmd.Flags().Lookup("proxy"))
+	_ = viper.BindPFlag("ca-file", cmd.Flags().Lookup("ca-file"))
+	_ = viper.BindPFlag("insecure-skip-verify", cmd.Flags().Lookup("insecure-skip-verify"))
+	_ = viper.BindPFlag("ports", cmd.Flags().Lookup("ports"))
+	_ = viper.BindPFlag("include-tags", cmd.Flags().Lookup("include-tags"))
+	_ = viper.BindPFlag("exclude-tags", cmd.Flags().Lookup("exclude-tags"))
+	_ = viper.BindPFlag("exclude-template-id", cmd.Flags().Lookup("exclude-template-id"))
+	_ = viper.BindPFlag("nuclei-cookie", cmd.Flags().Lookup("nuclei-cookie"))
+	_ = viper.BindPFlag("nuclei-workflow", cmd.Flags().Lookup("nuclei-workflow"))
+	_ = viper.BindPFlag("nuclei-severity", cmd.Flags().Lookup("nuclei-severity"))
+
+	// Fall back to the conventional proxy environment variables
+	_ = viper.BindEnv("proxy", "YORO_PROXY", "HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy")
+
+	return cmd
+}
+
+func runScan(cmd *cobra.Command, _ []string) error {
+	if err := applyScanProfile(); err != nil {
+		return err
+	}
+	if viper.GetBool("templates-only") {
+		return listTemplates(cmd)
+	}
+
+	var (
+		targets   []string
+		endpoints map[string][]string
+		err       error
+	)
+	if file := viper.GetString("url-list"); file != "" {
+		targets, endpoints, err = urlListTargets(file)
+	} else {
+		targets, err = scanTargets()
+	}
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	attest := viper.GetString("attest")
+	if attest == "" {
+		return usageErrorf("please provide --attest to confirm authorization")
+	}
+
+	concurrency := viper.GetInt("concurrency")
+	if concurrency < 1 {
+		return usageErrorf("--concurrency must be at least 1")
+	}
+	toStdout := viper.GetString("output") == utils.StdoutOutput
+	if (viper.GetBool("stdout") || toStdout) && viper.GetBool("summary-json") {
+		return usageErrorf("--summary-json and --stdout (or -o -) both write to stdout; use one of them")
+	}
+	if toStdout && (viper.GetBool("keep-raw") || viper.GetBool("resume") || viper.GetBool("split-by-scanner")) {
+		return usageErrorf("--keep-raw, --resume and --split-by-scanner need an output directory, not -o -")
+	}
+	if viper.GetInt("max-findings") < 0 {
+		return usageErrorf("--max-findings must not be negative")
+	}
+	if err := validateNotify(); err != nil {
+		return withKind(ErrUsage, err)
+	}
+	if interval := viper.GetDuration("interval"); interval < 0 {
+		return usageErrorf("--interval must not be negative")
+	} else if interval > 0 && viper.GetBool("resume") {
+		return usageErrorf("--resume would skip every target after the first --interval run; use one of them")
+	}
+	if viper.GetInt("retries") < 0 {
+		return usageErrorf("--retries must not be negative")
+	}
+	timeouts, err := scanners.ParseTimeouts(viper.GetString("scanner-timeout"))
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	failOn := strings.ToLower(viper.GetString("fail-on"))
+	if failOn != "" && schema.SeverityRank(failOn) < 0 {
+		return usageErrorf("invalid --fail-on %q (expected critical, high, medium, low or info)", failOn)
+	}
+
+	excluded, err := excludedSeverities()
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+
+	names := scannerNames()
+	if err := validateScanners(names); err != nil {
+		return withKind(ErrUsage, err)
+	}
+	nopts, err := nucleiOptions(cmd)
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	nopts.URLs = endpoints
+	tlsOpts := scanners.TLSOptions{
+		InsecureSkipVerify: viper.GetBool("insecure-skip-verify"),
+		CAFile:             viper.GetString("ca-file"),
+	}
+	if _, err := tlsOpts.Config(); err != nil {
+		return withKind(ErrUsage, err)
+	}
+	ignore, err := loadIgnoreList()
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	severities, err := loadSeverityMap()
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	weights, err := scoreWeights()
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	labels, err := labelFlags(cmd, "env")
+	if err != nil {
+		return err
+	}
+	var baseline *schema.ScanResult
+	if path := viper.GetString("baseline"); path != "" {
+		b, err := reportpkg.LoadScanResultFile(path)
+		if err != nil {
+			return usageErrorf("failed to load --baseline: %w", err)
+		}
+		baseline = &b
+	}
+	if viper.GetBool("expand-subdomains") {
+		if targetType() != "url" || viper.GetString("url-list") != "" {
+			return usageErrorf("--expand-subdomains works with --target and --targets-file URL targets")
+		}
+		if targets, err = expandSubdomains(cmd.Context(), targets); err != nil {
+			return err
+		}
+	}
+	job := scanJob{
+		names: names,
+		opts: scanners.Options{
+			Nuclei:  nopts,
+			Masscan: scanners.MasscanOptions{Ports: viper.GetString("ports")},
+			TLS:     tlsOpts,
+			Stdout:  toolOut(),
+			Stderr:  toolOut(),
+		},
+		outDir:     viper.GetString("output"),
+		labels:     labels,
+		ignore:     ignore,
+		severities: severities,
+		weights:    weights,
+		excluded:   excluded,
+		timeouts:   timeouts,
+		retry: scanners.RetryPolicy{
+			Retries: viper.GetInt("retries"),
+			Backoff: viper.GetDuration("retry-backoff"),
+		},
+	}
+	outDir := job.outDir
+	if viper.GetBool("dry-run") {
+		selected, err := buildScanners(names, job.opts)
+		if err != nil {
+			return withKind(ErrUsage, err)
+		}
+		return printScanPlan(targets, selected, outDir, job.labels)
+	}
+
+	operator, err := scanOperator()
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+
+	if viper.GetBool("update-templates") && contains(names, "nuclei") {
+		if err := updateTemplates(cmd.Context(), false); err != nil {
+			return withKind(ErrScanner, err)
+		}
+	}
+
+	pass := scanPass{
+		job:         job,
+		targets:     targets,
+		attest:      attest,
+		operator:    operator,
+		concurrency: concurrency,
+		failOn:      failOn,
+		baseline:    baseline,
+	}
+	if interval := viper.GetDuration("interval"); interval > 0 {
+		return watchScans(cmd.Context(), pass, interval)
+	}
+	_, err = pass.run(cmd.Context())
+	return err
+}
+
+// scanPass is one scan of every target; --interval repeats it
+type scanPass struct {
+	job         scanJob
+	targets     []string
+	attest      string
+	operator    string
+	concurrency int
+	failOn      string
+	baseline    *schema.ScanResult
+	// previous is the last run's result per target; its new findings are listed
+	// when there is no --baseline
+	previous map[string]schema.ScanResult
+}
+
+// run scans the targets and returns the saved results; the error covers failed
+// targets and --fail-on
+func (p scanPass) run(ctx context.Context) ([]schema.ScanResult, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		skipped  int
+		scanned  int
+		failed   []string
+		firstErr error
+		worst    = -1
+		results  []schema.ScanResult
+		fresh    []schema.Finding      // new since the baseline or previous run, for issue trackers
+		reports  = map[string]string{} // finding ID -> report PDF for --jira-attach
+	)
+	sem := make(chan struct{}, p.concurrency)
+	for i, target := range p.targets {
+		if viper.GetBool("resume") && hasCompleteResult(p.job.outDir, target, p.job.labels) {
+			logf("⏭️  Skipping %s (complete results.json found)\n", target)
+			skipped++
+			continue
+		}
+
+		sem <- struct{}{}
+		if timedOut(ctx) {
+			<-sem
+			logf("🛑 --timeout reached; %d target(s) not started\n", len(p.targets)-i)
+			failed = append(failed, p.targets[i:]...)
+			if firstErr == nil {
+				firstErr = ctx.Err()
+			}
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			auth := schema.Authorization{
+				Attestation: p.attest,
+				Operator:    p.operator,
+				Target:      target,
+				Timestamp:   time.Now(),
 			}
-			attest := viper.GetString("attest")
-			if attest == "" {
-				return errors.New("please provide --attest to confirm authorization")
+			res, err := scanTarget(ctx, p.job, target, auth)
+			var pdf string
+			if err == nil {
+				pdf = issueReport(utils.ResultDir(res, p.job.outDir))
 			}
 
-			fmt.Printf("🚀 Running nuclei scan for %s\n", target)
-			findings, err := scanners.RunNuclei(target)
+			mu.Lock()
+			defer mu.Unlock()
 			if err != nil {
-				return err
+				if firstErr == nil {
+					firstErr = err
+				}
+				if len(p.targets) > 1 {
+					logf("❌ Scan of %s failed: %v\n", target, err)
+				}
+				failed = append(failed, target)
+				return
+			}
+			scanned++
+			results = append(results, res)
+			gate, news := res.Findings, res.Findings
+			if p.baseline != nil {
+				gate = rules.NewFindings(res.Findings, p.baseline.Findings)
+				news = gate
+				printNewFindings(target, gate, "the baseline")
+			} else if prev, ok := p.previous[target]; ok {
+				news = rules.NewFindings(res.Findings, prev.Findings)
+				printNewFindings(target, news, "the previous run")
+			}
+			fresh = append(fresh, news...)
+			if pdf != "" {
+				for _, f := range news {
+					reports[f.ID] = pdf
+				}
+			}
+			for _, f := range gate {
+				if f.Suppressed {
+					continue
+				}
+				worst = max(worst, schema.SeverityRank(f.Severity))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() == nil {
+		fileIssues(ctx, fresh, reports)
+	}
+
+	if path := viper.GetString("metrics-file"); path != "" && len(results) > 0 {
+		if err := utils.WriteFileAtomic(path, reportpkg.FormatMetrics(results), 0644); err != nil {
+			logf("⚠️  Failed to write metrics: %v\n", err)
+		} else {
+			logf("📈 Metrics: %s\n", path)
+		}
+	}
+
+	if viper.GetBool("summary-json") {
+		if err := printSummaries(p.targets, results); err != nil {
+			return results, err
+		}
+	}
+
+	if len(p.targets) == 1 && firstErr != nil {
+		return results, firstErr
+	}
+
+	if len(p.targets) > 1 || skipped > 0 {
+		logf("📊 Targets: %d scanned, %d skipped, %d failed\n", scanned, skipped, len(failed))
+	}
+	if len(failed) > 0 {
+		err := fmt.Errorf("%d of %d targets failed (rerun with --resume to retry them)", len(failed), len(p.targets))
+		if errors.Is(firstErr, ErrScanner) {
+			err = withKind(ErrScanner, err)
+		}
+		return results, err
+	}
+	if p.failOn != "" && worst >= schema.SeverityRank(p.failOn) {
+		what := "findings"
+		if p.baseline != nil {
+			what = "new findings (not in --baseline)"
+		}
+		return results, &exitError{kind: ErrThreshold, err: fmt.Errorf("%s at or above %s severity were reported (--fail-on)", what, p.failOn)}
+	}
+	return results, nil
+}
+
+// printSummaries writes one compact JSON summary per scanned target to stdout,
+// in the order the targets were given
+func printSummaries(targets []string, results []schema.ScanResult) error {
+	order := make(map[string]int, len(targets))
+	for i, t := range targets {
+		order[t] = i
+	}
+	sort.SliceStable(results, func(i, j int) bool { return order[results[i].Target] < order[results[j].Target] })
+	enc := json.NewEncoder(os.Stdout)
+	for _, res := range results {
+		if err := enc.Encode(reportpkg.Summarize(res)); err != nil {
+			return fmt.Errorf("failed to encode summary: %w", err)
+		}
+	}
+	return nil
+}
+
+// printNewFindings lists the findings of target that are not in the reference
+// scan, named by against
+func printNewFindings(target string, findings []schema.Finding, against string) {
+	if len(findings) == 0 {
+		logf("🟰 No new findings for %s compared to %s\n", target, against)
+		return
+	}
+	logf("🆕 %d new finding(s) for %s compared to %s:\n", len(findings), target, against)
+	for _, f := range findings {
+		logf("   [%s] %s %s\n", strings.ToUpper(f.Severity), f.Template, f.Evidence)
+	}
+}
+
+// scanJob is what every per-target scan in one invocation shares
+type scanJob struct {
+	names      []string
+	opts       scanners.Options
+	outDir     string
+	labels     map[string]string
+	ignore     *rules.IgnoreList
+	severities *rules.SeverityMap
+	weights    map[string]int
+	excluded   []string // severities dropped before saving
+	retry      scanners.RetryPolicy
+	timeouts   scanners.Timeouts
+}
+
+// scanTarget runs the scanners against one target and saves its results.json
+func scanTarget(ctx context.Context, job scanJob, target string, auth schema.Authorization) (schema.ScanResult, error) {
+	outDir := job.outDir
+	if err := utils.AppendAuditLog(outDir, auth); err != nil {
+		return schema.ScanResult{}, err
+	}
+
+	// The result directory is fixed up front so scanners can save raw reports into it
+	stamp := time.Now()
+	var view *progressView
+	opts := job.opts
+	if viper.GetBool("keep-raw") {
+		opts.RawDir = utils.ResultDir(schema.ScanResult{Target: target, Labels: job.labels, Timestamp: stamp}, outDir)
+	}
+	// The live progress line needs a terminal and a single scanner running at a time
+	if viper.GetBool("progress") && !viper.GetBool("quiet") && viper.GetInt("concurrency") <= 1 && len(job.names) == 1 && colorEnabled(os.Stdout) {
+		view = newProgressView(os.Stdout, target)
+		opts.Progress = view.Update
+	}
+	selected, err := buildScanners(job.names, opts)
+	if err != nil {
+		return schema.ScanResult{}, err
+	}
+
+	meta := make([]schema.ScannerMeta, len(selected))
+	for i, s := range selected {
+		meta[i] = scanners.Meta(ctx, s, target)
+	}
+
+	// Scanners run side by side; a failing one is recorded without stopping the others
+	started := time.Now()
+	found := make([][]schema.Finding, len(selected))
+	errs := make([]error, len(selected))
+	var wg sync.WaitGroup
+	for i, s := range selected {
+		logf("🚀 Running %s scan for %s\n", s.Name(), target)
+		if viper.GetBool("verbose") {
+			logf("   %s\n", commandLine(s, target))
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info, _ := scanners.Lookup(s.Name())
+			live := view != nil && info.StreamsProgress
+			if live {
+				view.Start()
+				defer view.Stop()
+			}
+			retry := job.retry
+			retry.OnRetry = func(attempt int, wait time.Duration, err error) {
+				logf("🔁 %s scan of %s failed (%v); retrying in %s (attempt %d of %d)\n",
+					s.Name(), target, err, wait, attempt, retry.Retries+1)
 			}
+			found[i], errs[i] = runScanner(ctx, s, target, retry, job.timeouts.For(s.Name()))
+		}()
+	}
+	wg.Wait()
 
-			res := schema.ScanResult{
-				Target:    target,
-				Timestamp: time.Now(),
-				Findings:  findings,
+	var findings []schema.Finding
+	var failed []string
+	for i, s := range selected {
+		if errs[i] != nil {
+			meta[i].Status, meta[i].Error = schema.ScannerFailed, errs[i].Error()
+			var se *scanners.ScannerError
+			if errors.As(errs[i], &se) {
+				meta[i].ExitCode, meta[i].Stderr = se.ExitCode, se.Stderr
+			}
+			if timedOut(ctx) {
+				meta[i].Error = "stopped by --timeout: " + meta[i].Error
+			}
+			// A scanner stopped by a deadline returns what it found until then
+			if n := len(found[i]); n > 0 {
+				meta[i].Error += fmt.Sprintf(" (kept %d findings reported before it stopped)", n)
+				findings = append(findings, found[i]...)
+			}
+			failed = append(failed, s.Name())
+			if len(selected) > 1 {
+				logf("❌ %s scan of %s failed: %v\n", s.Name(), target, errs[i])
 			}
+			continue
+		}
+		meta[i].Status = schema.ScannerOK
+		findings = append(findings, found[i]...)
+	}
+	// On --timeout the result is saved even when no scanner finished, so the run
+	// leaves a record of what was attempted
+	if len(failed) == len(selected) && !timedOut(ctx) {
+		return schema.ScanResult{}, withKind(ErrScanner, errors.Join(errs...))
+	}
+	findings = scanners.MergeFindings(findings)
+	scanners.AssignStableIDs(findings)
+	applySeverityMap(job.severities, findings)
+	findings = excludeSeverities(job.excluded, findings)
+	applyIgnoreList(job.ignore, findings)
+	maxFindings := viper.GetInt("max-findings")
+	findings, truncated := scanners.CapFindings(findings, maxFindings)
+	if truncated {
+		logf("⚠️  %s produced more than %d findings; keeping the %d most severe (--max-findings)\n", target, maxFindings, maxFindings)
+	}
 
-			outDir := viper.GetString("output")
-			file, err := utils.SaveResult(res, outDir)
+	res := schema.ScanResult{
+		Target:        target,
+		Labels:        job.labels,
+		Timestamp:     stamp,
+		StartedAt:     started,
+		FinishedAt:    time.Now(),
+		Authorization: &auth,
+		ScannerMeta:   meta,
+		ScoreWeights:  job.weights,
+		Findings:      findings,
+
+		ExcludedSeverities: job.excluded,
+	}
+	if truncated {
+		res.Truncated, res.MaxFindings = true, maxFindings
+	}
+
+	file, err := utils.SaveResult(res, outDir)
+	if err != nil {
+		return schema.ScanResult{}, err
+	}
+	if outDir != utils.StdoutOutput {
+		artifacts := map[string]string{"json": file}
+		if viper.GetBool("split-by-scanner") {
+			parts, err := utils.SaveResultByScanner(res, filepath.Dir(file))
 			if err != nil {
-				return err
+				return schema.ScanResult{}, err
+			}
+			for name, path := range parts {
+				artifacts["json:"+name] = path
 			}
+		}
+		if _, err := reportpkg.UpdateManifest(filepath.Dir(file), artifacts); err != nil {
+			return schema.ScanResult{}, err
+		}
+		if err := reportpkg.AppendHistory(outDir, filepath.Dir(file), res); err != nil {
+			return schema.ScanResult{}, err
+		}
+	}
 
-			fmt.Printf("✅ Scan complete. Results saved to %s\n", file)
-			fmt.Printf("   Total findings: %d\n", len(findings))
-			return nil
-		},
+	if viper.GetBool("stdout") && outDir != utils.StdoutOutput {
+		// SaveResult's stdout output holds the lock that keeps concurrent scans apart
+		if _, err := utils.SaveResult(res, utils.StdoutOutput); err != nil {
+			return schema.ScanResult{}, err
+		}
 	}
 
-	cmd.Flags().String("target", "", "Target to scan (URL or domain)")
-	cmd.Flags().String("attest", "", "Authorization statement (e.g., 'I am authorized to test this target')")
-	_ = viper.BindPFlag("target", cmd.Flags().Lookup("target"))
-	_ = viper.BindPFlag("attest", cmd.Flags().Lookup("attest"))
+	logf("✅ Scan complete. Results saved to %s\n", file)
+	logf("   Total findings: %d (took %s)\n", len(findings), res.Duration().Round(time.Second))
+	if len(failed) > 0 {
+		return res, withKind(ErrScanner, fmt.Errorf("%d of %d scanners failed for %s (%s); results are incomplete",
+			len(failed), len(selected), target, strings.Join(failed, ", ")))
+	}
+	return res, nil
+}
 
-	return cmd
+// runScanner runs s with retries, all within its time limit (none when limit is zero)
+func runScanner(ctx context.Context, s scanners.Scanner, target string, retry scanners.RetryPolicy, limit time.Duration) ([]schema.Finding, error) {
+	if limit <= 0 {
+		return scanners.RunWithRetry(ctx, s, target, retry)
+	}
+	tctx, cancel := context.WithTimeout(ctx, limit)
+	defer cancel()
+	findings, err := scanners.RunWithRetry(tctx, s, target, retry)
+	if err != nil && ctx.Err() == nil && errors.Is(tctx.Err(), context.DeadlineExceeded) {
+		return findings, fmt.Errorf("%s timed out after %s (raise it with --scanner-timeout): %w", s.Name(), limit, err)
+	}
+	return findings, err
+}
+
+// scannerNames returns the --scanners selection; image targets default to trivy
+func scannerNames() []string {
+	if targetType() == "image" && !viper.IsSet("scanners") {
+		return []string{"trivy"}
+	}
+	var names []string
+	for _, n := range viper.GetStringSlice("scanners") {
+		if n = strings.ToLower(strings.TrimSpace(n)); n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+func validateScanners(names []string) error {
+	if len(names) == 0 {
+		return errors.New("please select at least one scanner with --scanners (see `yoro scanners`)")
+	}
+	for _, n := range names {
+		info, ok := scanners.Lookup(n)
+		if !ok {
+			return fmt.Errorf("unknown scanner %q (see `yoro scanners`)", n)
+		}
+		if info.TargetType != targetType() {
+			return fmt.Errorf("scanner %q does not support --target-type %s", n, targetType())
+		}
+	}
+	return nil
+}
+
+// targetType is "url" (default) or "image" for container images
+func targetType() string {
+	return strings.ToLower(strings.TrimSpace(viper.GetString("target-type")))
+}
+
+// buildScanners instantiates the selected scanners from the registry
+func buildScanners(names []string, opts scanners.Options) ([]scanners.Scanner, error) {
+	var out []scanners.Scanner
+	for _, n := range names {
+		s, err := scanners.New(n, opts)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func commandLine(s scanners.Scanner, target string) string {
+	if cl, ok := s.(scanners.CommandLiner); ok {
+		return cl.CommandLine(target)
+	}
+	return "(built-in)"
+}
+
+// scanTargets collects, normalizes and scope-checks the targets to scan
+func scanTargets() ([]string, error) {
+	var raw []string
+	switch t := viper.GetString("target"); {
+	// Piped targets are read without --target -, but never from a terminal
+	case t == "-", t == "" && viper.GetString("targets-file") == "" && !isTerminal(os.Stdin):
+		lines, err := utils.ReadLinesFrom(os.Stdin, "stdin")
+		if err != nil {
+			return nil, err
+		}
+		if t == "-" && len(lines) == 0 {
+			return nil, errors.New("--target - read no targets from stdin")
+		}
+		raw = append(raw, lines...)
+	case t != "":
+		raw = append(raw, t)
+	}
+	if file := viper.GetString("targets-file"); file != "" {
+		lines, err := utils.ReadLines(file)
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, lines...)
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("please provide --target, --targets-file or targets on stdin")
+	}
+
+	switch targetType() {
+	case "url":
+	case "image":
+		return normalizeImages(raw)
+	default:
+		return nil, fmt.Errorf("invalid --target-type %q (expected url or image)", targetType())
+	}
+
+	scope, err := scanScope()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var targets []string
+	for _, r := range raw {
+		target, err := normalizeTarget(r)
+		if err != nil {
+			return nil, err
+		}
+		hosts := []string{target}
+		if utils.IsCIDR(target) {
+			if hosts, err = expandRange(target); err != nil {
+				return nil, err
+			}
+		}
+		for _, host := range hosts {
+			if err := scope.Check(host); err != nil {
+				return nil, err
+			}
+			if !seen[host] {
+				seen[host] = true
+				targets = append(targets, host)
+			}
+		}
+	}
+	return targets, nil
+}
+
+// scanScope returns the validated scope.allow/scope.deny lists
+func scanScope() (utils.Scope, error) {
+	scope := utils.Scope{
+		Allow: viper.GetStringSlice("scope.allow"),
+		Deny:  viper.GetStringSlice("scope.deny"),
+	}
+	return scope, scope.Validate()
+}
+
+// urlListTargets reads a --url-list file and groups its URLs by origin: each
+// scheme://host[:port] becomes one target, scanned with its URLs as nuclei's -list
+func urlListTargets(file string) ([]string, map[string][]string, error) {
+	if viper.GetString("target") != "" || viper.GetString("targets-file") != "" {
+		return nil, nil, errors.New("--url-list cannot be combined with --target or --targets-file")
+	}
+	if targetType() != "url" {
+		return nil, nil, errors.New("--url-list needs --target-type url")
+	}
+	lines, err := utils.ReadLines(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil, fmt.Errorf("--url-list %s contains no URLs", file)
+	}
+	scope, err := scanScope()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var targets []string
+	endpoints := map[string][]string{}
+	seen := map[string]bool{}
+	for _, line := range lines {
+		u, err := url.Parse(line)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, nil, fmt.Errorf("invalid URL %q in --url-list %s (expected http:// or https://)", line, file)
+		}
+		origin := u.Scheme + "://" + strings.ToLower(u.Host)
+		if _, ok := endpoints[origin]; !ok {
+			if err := scope.Check(origin); err != nil {
+				return nil, nil, err
+			}
+			targets = append(targets, origin)
+		}
+		if !seen[line] {
+			seen[line] = true
+			endpoints[origin] = append(endpoints[origin], line)
+		}
+	}
+	return targets, endpoints, nil
+}
+
+// normalizeTarget applies --scheme to targets given without one
+func normalizeTarget(raw string) (string, error) {
+	return utils.NormalizeTargetScheme(raw, viper.GetString("scheme"))
+}
+
+// expandRange turns a CIDR target into one normalized target per host address
+func expandRange(cidr string) ([]string, error) {
+	bits, err := utils.CIDRHostBits(cidr)
+	if err != nil {
+		return nil, err
+	}
+	if bits > 16 && !viper.GetBool("confirm") {
+		return nil, fmt.Errorf("%s covers %d addresses; rerun with --confirm to expand ranges larger than /16", cidr, uint64(1)<<bits)
+	}
+	ips, err := utils.ExpandCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	logf("🌐 Expanding %s into %d hosts\n", cidr, len(ips))
+
+	targets := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		target, err := normalizeTarget(ip)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// expandSubdomains adds the in-scope subdomains subfinder finds for each domain
+// target, scanned with the scheme of the target they were found from
+func expandSubdomains(ctx context.Context, targets []string) ([]string, error) {
+	scope, err := scanScope()
+	if err != nil {
+		return nil, withKind(ErrUsage, err)
+	}
+	seen := map[string]bool{}
+	for _, t := range targets {
+		seen[t] = true
+	}
+	out := append([]string{}, targets...)
+	for _, t := range targets {
+		u, err := url.Parse(t)
+		if err != nil || u.Hostname() == "" || net.ParseIP(u.Hostname()) != nil {
+			continue
+		}
+		hosts, err := scanners.DiscoverSubdomains(ctx, u.Hostname(), scanners.Options{Stdout: toolOut(), Stderr: toolOut()})
+		if err != nil {
+			return nil, withKind(ErrScanner, fmt.Errorf("failed to discover subdomains of %s: %w", u.Hostname(), err))
+		}
+		added, outOfScope := 0, 0
+		for _, h := range hosts {
+			sub, err := utils.NormalizeTargetScheme(h, u.Scheme)
+			if err != nil || seen[sub] {
+				continue
+			}
+			if err := scope.Check(sub); err != nil {
+				outOfScope++
+				continue
+			}
+			seen[sub] = true
+			out = append(out, sub)
+			added++
+		}
+		logf("🌐 Expanding %s into %d subdomain(s)\n", u.Hostname(), added)
+		if outOfScope > 0 {
+			logf("⚠️  %d subdomain(s) of %s are out of scope and not scanned\n", outOfScope, u.Hostname())
+		}
+	}
+	return out, nil
+}
+
+// normalizeImages trims and de-duplicates container image references
+func normalizeImages(raw []string) ([]string, error) {
+	seen := map[string]bool{}
+	var images []string
+	for _, r := range raw {
+		img := strings.TrimSpace(r)
+		if img == "" || strings.ContainsAny(img, " \t") || strings.Contains(img, "://") {
+			return nil, fmt.Errorf("invalid image reference %q (expected e.g. nginx:1.25 or ghcr.io/org/app:tag)", r)
+		}
+		if !seen[img] {
+			seen[img] = true
+			images = append(images, img)
+		}
+	}
+	return images, nil
+}
+
+// nucleiOptions builds and validates the nuclei options from flags/config
+func nucleiOptions(cmd *cobra.Command) (scanners.NucleiOptions, error) {
+	opts := scanners.NucleiOptions{
+		Proxy:       viper.GetString("proxy"),
+		IncludeRaw:  viper.GetBool("include-raw"),
+		MaxFindings: viper.GetInt("max-findings"),
+	}
+	if opts.Proxy != "" {
+		if err := scanners.ValidateProxy(opts.Proxy); err != nil {
+			return opts, err
+		}
+	}
+
+	// Read headers straight from the flag: viper would split values on commas
+	headers, _ := cmd.Flags().GetStringArray("header")
+	for _, h := range headers {
+		if err := scanners.ValidateHeader(h); err != nil {
+			return opts, err
+		}
+		opts.Headers = append(opts.Headers, h)
+	}
+	if cookie := viper.GetString("nuclei-cookie"); cookie != "" {
+		opts.Headers = append(opts.Headers, "Cookie: "+cookie)
+	}
+
+	opts.IncludeTags = cleanList(viper.GetStringSlice("include-tags"))
+	opts.ExcludeTags = cleanList(viper.GetStringSlice("exclude-tags"))
+	opts.ExcludeIDs = cleanList(viper.GetStringSlice("exclude-template-id"))
+	opts.Severities = cleanList(viper.GetStringSlice("nuclei-severity"))
+	for i, sev := range opts.Severities {
+		opts.Severities[i] = strings.ToLower(sev)
+	}
+	if err := scanners.ValidateTemplateFilters(opts); err != nil {
+		return opts, err
+	}
+	if opts.Workflow = viper.GetString("nuclei-workflow"); opts.Workflow != "" {
+		if err := scanners.ValidateWorkflow(opts.Workflow); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// cleanList trims entries and drops empty and duplicate ones
+func cleanList(in []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, v := range in {
+		if v = strings.TrimSpace(v); v != "" && !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// scanOperator resolves who is running the scan for the audit trail
+func scanOperator() (string, error) {
+	operator := viper.GetString("operator")
+	if operator == "" {
+		operator = os.Getenv("USER")
+	}
+	if operator == "" {
+		if u, err := user.Current(); err == nil {
+			operator = u.Username
+		}
+	}
+	if operator == "" {
+		return "", errors.New("please provide --operator (could not determine it from $USER)")
+	}
+	return operator, nil
+}
+
+// hasCompleteResult reports whether outDir already holds a loadable results.json for target
+// with the same labels, in which every scanner succeeded
+func hasCompleteResult(outDir, target string, labels map[string]string) bool {
+	dirs, err := utils.ResultDirs(target, outDir)
+	if err != nil {
+		return false
+	}
+	for _, dir := range dirs {
+		res, err := reportpkg.LoadScanResult(dir)
+		if err == nil && res.Target == target && maps.Equal(res.Labels, labels) && len(res.FailedScanners()) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// listTemplates prints the nuclei templates the current selection would run; unlike
+// --dry-run it queries nuclei itself, but it never contacts a target
+func listTemplates(cmd *cobra.Command) error {
+	if !contains(scannerNames(), "nuclei") {
+		return usageErrorf("--templates-only lists nuclei templates, but nuclei is not in --scanners")
+	}
+	nopts, err := nucleiOptions(cmd)
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	ids, err := scanners.ListNucleiTemplates(cmd.Context(), scanners.Options{Nuclei: nopts})
+	if err != nil {
+		return withKind(ErrScanner, err)
+	}
+	writef(os.Stdout, "🧪 %d nuclei template(s) match the selection\n", len(ids))
+	for _, id := range ids {
+		fmt.Println(id)
+	}
+	return nil
+}
+
+// printScanPlan shows what a scan would do and surfaces the errors a real run would hit
+func printScanPlan(targets []string, selected []scanners.Scanner, outDir string, labels map[string]string) error {
+	var names []string
+	for _, s := range selected {
+		names = append(names, s.Name())
+	}
+	writef(os.Stdout, "🧪 Dry run: nothing will be executed\n")
+	writef(os.Stdout, "   Scanners: %s\n", strings.Join(names, ", "))
+	for _, target := range targets {
+		writef(os.Stdout, "   Target:   %s\n", target)
+		for _, s := range selected {
+			writef(os.Stdout, "     Command: %s\n", commandLine(s, target))
+		}
+		writef(os.Stdout, "     Output:  %s\n", utils.ResultDir(schema.ScanResult{Target: target, Labels: labels, Timestamp: time.Now()}, outDir))
+		if viper.GetBool("resume") && hasCompleteResult(outDir, target, labels) {
+			writef(os.Stdout, "     Resume:  already complete, would be skipped\n")
+		}
+	}
+
+	for _, name := range names {
+		info, _ := scanners.Lookup(name)
+		if info.Binary == "" {
+			continue
+		}
+		if _, err := exec.LookPath(info.Binary); err != nil {
+			return withKind(ErrScanner, fmt.Errorf("%s binary not found in PATH: %w", info.Binary, err))
+		}
+	}
+	writef(os.Stdout, "✅ Plan is valid\n")
+	return nil
 }
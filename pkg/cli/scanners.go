@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/scanners"
+)
+
+func newScannersCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "scanners",
+		Short: "List available scanners and whether their binaries are installed",
+		Run: func(cmd *cobra.Command, args []string) {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tTARGET\tBINARY\tSTATUS\tDESCRIPTION")
+			for _, info := range scanners.ListScanners() {
+				binary, status := info.Binary, "ready"
+				if binary == "" {
+					binary, status = "-", "built-in"
+				} else if _, err := exec.LookPath(binary); err != nil {
+					status = "missing"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", info.Name, info.TargetType, binary, status, info.Description)
+			}
+			_ = w.Flush()
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/serve"
+)
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "serve",
+		Short:   "Serve scan reports over HTTP with an index of available scans",
+		Example: "yoro serve --dir ./reports --addr 127.0.0.1:8080",
+		RunE:    runServe,
+	}
+
+	cmd.Flags().String("dir", "", "Root directory of the scan result directories (default: --output)")
+	cmd.Flags().String("addr", "127.0.0.1:8080", "Listen address host:port (use 0.0.0.0:8080 to listen on all interfaces)")
+	cmd.Flags().String("basic-auth", "", "Require HTTP basic auth as user:pass (prefer YORO_SERVE_BASIC_AUTH to keep it out of the process list)")
+	cmd.Flags().String("tls-cert", "", "TLS certificate (PEM) to serve HTTPS; needs --tls-key")
+	cmd.Flags().String("tls-key", "", "TLS private key (PEM) for --tls-cert")
+	_ = viper.BindPFlag("serve.dir", cmd.Flags().Lookup("dir"))
+	_ = viper.BindPFlag("serve.addr", cmd.Flags().Lookup("addr"))
+	_ = viper.BindPFlag("serve.basic-auth", cmd.Flags().Lookup("basic-auth"))
+	_ = viper.BindPFlag("serve.tls-cert", cmd.Flags().Lookup("tls-cert"))
+	_ = viper.BindPFlag("serve.tls-key", cmd.Flags().Lookup("tls-key"))
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, _ []string) error {
+	dir := viper.GetString("serve.dir")
+	if dir == "" {
+		dir = viper.GetString("output")
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return usageErrorf("--dir %s is not a directory", dir)
+	}
+
+	certFile, keyFile := viper.GetString("serve.tls-cert"), viper.GetString("serve.tls-key")
+	if (certFile == "") != (keyFile == "") {
+		return usageErrorf("--tls-cert and --tls-key must be given together")
+	}
+	useTLS := certFile != ""
+
+	var handler http.Handler = serve.NewHandler(dir)
+	if auth := viper.GetString("serve.basic-auth"); auth != "" {
+		user, pass, ok := strings.Cut(auth, ":")
+		if !ok || user == "" || pass == "" {
+			return usageErrorf("invalid --basic-auth (expected user:pass)")
+		}
+		handler = serve.BasicAuth(handler, user, pass)
+		if !useTLS {
+			logf("⚠️  Basic auth without --tls-cert sends the password in clear text\n")
+		}
+	} else {
+		logf("⚠️  The report server has no authentication: anyone who can reach %s can read every report (see --basic-auth)\n", viper.GetString("serve.addr"))
+	}
+
+	srv := &http.Server{
+		Addr:              viper.GetString("serve.addr"),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	logf("🌐 Serving %s on %s://%s (Ctrl-C to stop)\n", dir, scheme, srv.Addr)
+	var err error
+	if useTLS {
+		err = srv.ListenAndServeTLS(certFile, keyFile)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve reports: %w", err)
+	}
+	return nil
+}
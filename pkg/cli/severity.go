@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	reportpkg "github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/rules"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+func init() {
+	schema.UnknownSeverity = func(raw string) {
+		logf("⚠️  Unknown severity %q treated as info\n", raw)
+	}
+}
+
+// loadSeverityMap reads --severity-map; nil when none is given
+func loadSeverityMap() (*rules.SeverityMap, error) {
+	file := viper.GetString("severity-map")
+	if file == "" {
+		return nil, nil
+	}
+	return rules.LoadSeverityMap(file)
+}
+
+// applySeverityMap reclassifies findings and reports how many were changed
+func applySeverityMap(m *rules.SeverityMap, findings []schema.Finding) {
+	if n := m.Apply(findings); n > 0 {
+		logf("🎚️  %d finding(s) reclassified by %s\n", n, m.Path)
+	}
+}
+
+// excludedSeverities returns the validated, lowercase --exclude-severity list
+func excludedSeverities() ([]string, error) {
+	var out []string
+	for _, sev := range viper.GetStringSlice("exclude-severity") {
+		sev = strings.ToLower(strings.TrimSpace(sev))
+		if sev == "" || slices.Contains(out, sev) {
+			continue
+		}
+		if schema.SeverityRank(sev) < 0 {
+			return nil, fmt.Errorf("invalid --exclude-severity %q (expected critical, high, medium, low or info)", sev)
+		}
+		out = append(out, sev)
+	}
+	return out, nil
+}
+
+// excludeSeverities drops the findings whose severity is in exclude, after any
+// severity map has reclassified them
+func excludeSeverities(exclude []string, findings []schema.Finding) []schema.Finding {
+	if len(exclude) == 0 {
+		return findings
+	}
+	kept := findings[:0]
+	for _, f := range findings {
+		if !slices.Contains(exclude, reportpkg.SeverityOf(f)) {
+			kept = append(kept, f)
+		}
+	}
+	if n := len(findings) - len(kept); n > 0 {
+		logf("🎚️  %d %s finding(s) dropped (--exclude-severity)\n", n, strings.Join(exclude, "/"))
+	}
+	return kept
+}
+
+// scoreWeights returns the per-severity score penalties, with any score-weights
+// config overrides applied
+func scoreWeights() (map[string]int, error) {
+	var overrides map[string]int
+	if err := viper.UnmarshalKey("score-weights", &overrides); err != nil {
+		return nil, fmt.Errorf("invalid score-weights config (expected severity: weight): %w", err)
+	}
+	return reportpkg.ScoreWeights(overrides)
+}
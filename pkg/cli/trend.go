@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	reportpkg "github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+func newTrendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "trend",
+		Short:   "Show finding counts by severity over time for one target",
+		Example: "yoro trend --dir ./reports --target example.com --html trend.html",
+		RunE:    runTrend,
+	}
+
+	cmd.Flags().String("dir", "", "Directory holding the scan result directories (default --output)")
+	cmd.Flags().String("target", "", "Target whose scans to compare")
+	cmd.Flags().String("html", "", "Also write an HTML chart to this file")
+	cmd.Flags().String("since", "", "Ignore scans before this date (2006-01-02, RFC3339 or age such as 90d)")
+	cmd.Flags().String("until", "", "Ignore scans after this date (a date includes the whole day)")
+	addLabelFlags(cmd, "trend.env", "Only include scans with this env label", "Only include scans with this key=value label (repeatable)")
+	_ = viper.BindPFlag("trend.dir", cmd.Flags().Lookup("dir"))
+	_ = viper.BindPFlag("trend.target", cmd.Flags().Lookup("target"))
+	_ = viper.BindPFlag("trend.html", cmd.Flags().Lookup("html"))
+	_ = viper.BindPFlag("trend.since", cmd.Flags().Lookup("since"))
+	_ = viper.BindPFlag("trend.until", cmd.Flags().Lookup("until"))
+	return cmd
+}
+
+func runTrend(cmd *cobra.Command, _ []string) error {
+	raw := viper.GetString("trend.target")
+	if raw == "" {
+		return errors.New("please provide --target")
+	}
+	target, err := utils.NormalizeTarget(raw)
+	if err != nil {
+		return err
+	}
+	window, err := utils.ParseTimeRange(viper.GetString("trend.since"), viper.GetString("trend.until"), time.Now())
+	if err != nil {
+		return withKind(ErrUsage, err)
+	}
+	labels, err := labelFlags(cmd, "trend.env")
+	if err != nil {
+		return err
+	}
+	filter := scanFilter{window: window, labels: labels}
+	dir := viper.GetString("trend.dir")
+	if dir == "" {
+		dir = viper.GetString("output")
+	}
+
+	dirs, err := utils.ResultDirs(target, dir)
+	if err != nil {
+		return err
+	}
+	var points []reportpkg.TrendPoint
+	for _, d := range dirs {
+		res, err := reportpkg.LoadScanResult(d)
+		if err != nil {
+			logf("⚠️  Skipping %s: %v\n", d, err)
+			continue
+		}
+		if res.Target != target || !filter.match(res) {
+			continue
+		}
+		points = append(points, reportpkg.NewTrendPoint(d, res))
+	}
+	if len(points) == 0 {
+		if window != (utils.TimeRange{}) || labels != nil {
+			return fmt.Errorf("no scans of %s in %s match --since/--until/--env/--label", target, dir)
+		}
+		return fmt.Errorf("no scans of %s found in %s", target, dir)
+	}
+	reportpkg.SortTrend(points)
+
+	sevs := []string{"critical", "high", "medium", "low", "info"}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCAN TIME\t"+strings.ToUpper(strings.Join(sevs, "\t"))+"\tTOTAL")
+	for _, p := range points {
+		fmt.Fprintf(w, "%s", p.Time.UTC().Format(time.RFC3339))
+		for _, sev := range sevs {
+			fmt.Fprintf(w, "\t%d", p.Counts[sev])
+		}
+		fmt.Fprintf(w, "\t%d\n", p.Total)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if path := viper.GetString("trend.html"); path != "" {
+		out, err := reportpkg.GenerateTrendHTML(target, points, path, reportpkg.Options{})
+		if err != nil {
+			return err
+		}
+		logf("📈 Trend chart: %s\n", out)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/scanners"
+	"github.com/yorozuya-cybersecurity/yorosec-agent/pkg/utils"
+)
+
+// templatesStamp is the cache file holding the last successful template update
+const templatesStamp = "nuclei-templates.updated"
+
+func newUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update nuclei templates",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return updateTemplates(cmd.Context(), viper.GetBool("update.force"))
+		},
+	}
+
+	cmd.Flags().Bool("force", false, "Update even if templates were updated within --max-age")
+	cmd.Flags().Duration("max-age", 24*time.Hour, "Skip the update when the last one is more recent than this (config: update.max-age)")
+	_ = viper.BindPFlag("update.force", cmd.Flags().Lookup("force"))
+	_ = viper.BindPFlag("update.max-age", cmd.Flags().Lookup("max-age"))
+	return cmd
+}
+
+// updateTemplates runs `nuclei -update-templates` unless offline or recently updated
+func updateTemplates(ctx context.Context, force bool) error {
+	if viper.GetBool("offline") {
+		logf("⏭️  Offline mode: skipping nuclei template update\n")
+		return nil
+	}
+	if !force {
+		if last, err := utils.ReadStamp(templatesStamp); err == nil && time.Since(last) < viper.GetDuration("update.max-age") {
+			logf("⏭️  Nuclei templates were updated %s ago; skipping (use `yoro update --force`)\n", time.Since(last).Round(time.Minute))
+			return nil
+		}
+	}
+
+	before, _ := scanners.NucleiTemplatesVersion(ctx)
+	logf("⬇️  Updating nuclei templates (installed: %s)\n", versionOrNone(before))
+	if err := scanners.UpdateNucleiTemplates(ctx, scanners.Options{Stdout: toolOut(), Stderr: toolOut()}); err != nil {
+		return err
+	}
+	after, _ := scanners.NucleiTemplatesVersion(ctx)
+	if before == after {
+		logf("✅ Nuclei templates are up to date (%s)\n", versionOrNone(after))
+	} else {
+		logf("✅ Nuclei templates updated: %s → %s\n", versionOrNone(before), versionOrNone(after))
+	}
+	return utils.WriteStamp(templatesStamp, time.Now())
+}
+
+func versionOrNone(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	reportpkg "github.com/yorozuya-cybersecurity/yorosec-agent/internal/report"
+)
+
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check a results.json against the schema before generating reports from it",
+		Long: `Check a results.json (or results.yaml) the way report loading does, then list
+every problem found: missing fields, unknown severities, zero timestamps,
+out-of-range CVSS scores. Exits non-zero when there are any, so CI pipelines
+that produce results outside yoro can gate on it.`,
+		Example: "yoro validate --file ./build/results.json",
+		RunE:    runValidate,
+	}
+	cmd.Flags().String("file", "", "results.json or results.yaml file to check")
+	_ = viper.BindPFlag("validate.file", cmd.Flags().Lookup("file"))
+	return cmd
+}
+
+func runValidate(_ *cobra.Command, _ []string) error {
+	file := viper.GetString("validate.file")
+	if file == "" {
+		return usageErrorf("please provide --file")
+	}
+	res, err := reportpkg.LoadScanResultFile(file)
+	if err != nil {
+		return err
+	}
+	problems := res.Validate()
+	if len(problems) == 0 {
+		logf("✅ %s is valid (%d findings)\n", file, len(res.Findings))
+		return nil
+	}
+	for _, p := range problems {
+		fmt.Fprintf(os.Stdout, "%s: %s\n", file, p)
+	}
+	return fmt.Errorf("%s has %d problem(s)", file, len(problems))
+}
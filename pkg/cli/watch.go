@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// watchScans repeats pass every interval until interrupted. Each run saves to its
+// own timestamped directories and lists the findings new since the previous run;
+// a failed run is reported and the next one still happens.
+func watchScans(ctx context.Context, pass scanPass, interval time.Duration) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for run := 1; ; run++ {
+		logf("🚀 Scan run %d (every %s; Ctrl-C to stop)\n", run, interval)
+		results, err := pass.run(ctx)
+		if ctx.Err() != nil {
+			logf("🛑 Interrupted; stopping after run %d\n", run)
+			return nil
+		}
+		if err != nil {
+			logf("⚠️  Run %d: %v\n", run, err)
+		}
+
+		if pass.previous == nil {
+			pass.previous = map[string]schema.ScanResult{}
+		}
+		for _, res := range results {
+			pass.previous[res.Target] = res
+		}
+
+		logf("⏳ Next run at %s\n", time.Now().Add(interval).Format(time.RFC3339))
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logf("🛑 Interrupted; stopping after run %d\n", run)
+			return nil
+		case <-timer.C:
+		}
+	}
+}
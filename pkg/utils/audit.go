@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+// AppendAuditLog appends one JSON line per authorized scan to <outputDir>/audit.log.
+// With StdoutOutput there is no directory; the authorization stays in the results.
+func AppendAuditLog(outputDir string, auth schema.Authorization) error {
+	if outputDir == StdoutOutput {
+		return nil
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output dir: %w", err)
+	}
+
+	line, err := json.Marshal(auth)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	fh, err := os.OpenFile(filepath.Join(outputDir, "audit.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit.log: %w", err)
+	}
+	defer fh.Close()
+
+	if _, err := fh.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit.log: %w", err)
+	}
+	return nil
+}
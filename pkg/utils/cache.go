@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CacheDir is yoro's per-user cache directory (e.g. ~/.cache/yoro)
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache dir: %w", err)
+	}
+	return filepath.Join(dir, "yoro"), nil
+}
+
+// ReadStamp returns the time recorded by WriteStamp under name
+func ReadStamp(name string) (time.Time, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return time.Time{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+}
+
+// WriteStamp records t under name in the cache directory
+func WriteStamp(name string, t time.Time) error {
+	dir, err := CacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(t.UTC().Format(time.RFC3339)+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
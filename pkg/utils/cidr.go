@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// MaxCIDRHostBits is the largest range (a /8 for IPv4) ExpandCIDR will enumerate
+const MaxCIDRHostBits = 24
+
+// IsCIDR reports whether s is an address range such as "10.0.0.0/24" rather than a single target
+func IsCIDR(s string) bool {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "://") {
+		return false
+	}
+	_, err := netip.ParsePrefix(s)
+	return err == nil
+}
+
+// CIDRHostBits returns the number of host bits in cidr (8 for a /24, 16 for a /16)
+func CIDRHostBits(cidr string) (int, error) {
+	p, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return 0, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+	}
+	return p.Addr().BitLen() - p.Bits(), nil
+}
+
+// ExpandCIDR lists the host addresses in cidr. For IPv4 ranges larger than /31 the
+// network and broadcast addresses are skipped.
+func ExpandCIDR(cidr string) ([]string, error) {
+	p, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+	}
+	p = p.Masked()
+	hostBits := p.Addr().BitLen() - p.Bits()
+	if hostBits > MaxCIDRHostBits {
+		return nil, fmt.Errorf("CIDR %s is too large to expand (at most /%d)", p, p.Addr().BitLen()-MaxCIDRHostBits)
+	}
+
+	var hosts []string
+	for a := p.Addr(); p.Contains(a); a = a.Next() {
+		hosts = append(hosts, a.String())
+		if !a.Next().IsValid() {
+			break
+		}
+	}
+	if p.Addr().Is4() && hostBits > 1 {
+		hosts = hosts[1 : len(hosts)-1]
+	}
+	return hosts, nil
+}
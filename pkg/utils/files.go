@@ -1,39 +1,214 @@
 package utils
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
 
 	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
 )
 
-// SaveResult writes findings into a JSON file inside ./reports/<target_timestamp>/
+// ResultDir returns the per-scan directory <outputDir>/<target_timestamp>, or
+// <outputDir>/<target_env_timestamp> when the result has an env label
+func ResultDir(res schema.ScanResult, outputDir string) string {
+	name := SafeName(stripScheme(res.Target))
+	if env := res.Labels[schema.EnvLabel]; env != "" {
+		name += "_" + SafeName(env)
+	}
+	return filepath.Join(outputDir, name+"_"+res.Timestamp.Format("20060102_150405"))
+}
+
+// ResultDirs lists existing scan directories for target under outputDir, oldest first.
+// Names are matched by prefix rather than a glob, since safe names of IPv6 targets
+// keep their brackets.
+func ResultDirs(target, outputDir string) ([]string, error) {
+	entries, err := os.ReadDir(outputDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	prefix := SafeName(stripScheme(target)) + "_"
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), prefix) {
+			dirs = append(dirs, filepath.Join(outputDir, e.Name()))
+		}
+	}
+	return dirs, nil
+}
+
+// ReadLines reads a list file, skipping blank lines and '#' comments
+func ReadLines(path string) ([]string, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer fh.Close()
+	return ReadLinesFrom(fh, path)
+}
+
+// ReadLinesFrom is ReadLines for an open reader such as stdin; name is used in errors
+func ReadLinesFrom(r io.Reader, name string) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return lines, nil
+}
+
+// StdoutOutput as the output directory (-o -) sends results JSON to stdout
+// instead of saving it
+const StdoutOutput = "-"
+
+// stdoutMu keeps results of concurrent scans (--concurrency) from interleaving on
+// stdout, whether written for -o - or --stdout
+var stdoutMu sync.Mutex
+
+// SaveResult writes findings into a JSON file inside ./reports/<target_timestamp>/.
+// The file is replaced atomically, so an interrupted scan never leaves a partial one.
+// With StdoutOutput the results go to stdout and the returned path is "stdout".
 func SaveResult(res schema.ScanResult, outputDir string) (string, error) {
-	dir := filepath.Join(outputDir, safeName(res.Target)+"_"+res.Timestamp.Format("20060102_150405"))
+	if outputDir == StdoutOutput {
+		stdoutMu.Lock()
+		defer stdoutMu.Unlock()
+		if err := EncodeResult(os.Stdout, res); err != nil {
+			return "", err
+		}
+		return "stdout", nil
+	}
+	dir := ResultDir(res, outputDir)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create output dir: %w", err)
 	}
 
+	// Encode into a temp file and rename it over results.json on success
 	file := filepath.Join(dir, "results.json")
-	fh, err := os.Create(file)
+	err := writeAtomic(file, 0644, func(w io.Writer) error { return EncodeResult(w, res) })
 	if err != nil {
-		return "", fmt.Errorf("failed to create results.json: %w", err)
+		return "", err
 	}
-	defer fh.Close()
 
-	enc := json.NewEncoder(fh)
+	return file, nil
+}
+
+// SaveResultByScanner writes results.<scanner>.json into dir next to results.json
+// for every scanner in res, each a complete ScanResult holding only that scanner's
+// findings and metadata. A finding merged from several scanners is in each of their
+// files. It returns the written paths by scanner name.
+func SaveResultByScanner(res schema.ScanResult, dir string) (map[string]string, error) {
+	var names []string
+	for _, m := range res.ScannerMeta {
+		names = append(names, m.Name)
+	}
+	for _, f := range res.Findings {
+		names = append(names, strings.Split(f.Scanner, ",")...)
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	paths := map[string]string{}
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		part := res
+		part.ScannerMeta = nil
+		for _, m := range res.ScannerMeta {
+			if m.Name == name {
+				part.ScannerMeta = append(part.ScannerMeta, m)
+			}
+		}
+		part.Findings = []schema.Finding{}
+		for _, f := range res.Findings {
+			if slices.Contains(strings.Split(f.Scanner, ","), name) {
+				part.Findings = append(part.Findings, f)
+			}
+		}
+		file := filepath.Join(dir, "results."+SafeName(name)+".json")
+		if err := writeAtomic(file, 0644, func(w io.Writer) error { return EncodeResult(w, part) }); err != nil {
+			return paths, err
+		}
+		paths[name] = file
+	}
+	return paths, nil
+}
+
+// EncodeResult writes res as indented JSON, the same format as results.json,
+// stamped with the current schema version
+func EncodeResult(w io.Writer, res schema.ScanResult) error {
+	res.SchemaVersion = schema.CurrentVersion
+	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	if err := enc.Encode(res); err != nil {
-		return "", fmt.Errorf("failed to encode results: %w", err)
+		return fmt.Errorf("failed to encode results: %w", err)
 	}
+	return nil
+}
 
+// SaveResultYAML writes res as results.yaml into dir, a scan's result directory,
+// replacing any earlier one atomically
+func SaveResultYAML(res schema.ScanResult, dir string) (string, error) {
+	file := filepath.Join(dir, "results.yaml")
+	err := writeAtomic(file, 0644, func(w io.Writer) error { return EncodeResultYAML(w, res) })
+	if err != nil {
+		return "", err
+	}
 	return file, nil
 }
 
-// safeName replaces characters not safe for file paths
-func safeName(s string) string {
+// EncodeResultYAML writes res as YAML with the same keys and omissions as
+// results.json: it is encoded through the JSON tags, so the formats never drift
+func EncodeResultYAML(w io.Writer, res schema.ScanResult) error {
+	var buf bytes.Buffer
+	if err := EncodeResult(&buf, res); err != nil {
+		return err
+	}
+	// JSON is YAML, so decoding it as a node keeps the key order
+	var doc yaml.Node
+	if err := yaml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		return fmt.Errorf("failed to encode results as YAML: %w", err)
+	}
+	blockStyle(&doc)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode results as YAML: %w", err)
+	}
+	return enc.Close()
+}
+
+// blockStyle drops the flow and quoting styles carried over from JSON; the
+// encoder still quotes strings that would otherwise read as another type
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// SafeName replaces characters not safe for file paths
+func SafeName(s string) string {
 	invalid := []rune{'/', '\\', ':', '*', '?', '"', '<', '>', '|'}
 	rs := []rune(s)
 	for i, r := range rs {
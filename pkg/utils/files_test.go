@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+func TestResultDirs(t *testing.T) {
+	out := t.TempDir()
+	stamp := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var want []string
+	for _, target := range []string{"http://[::1]:8080", "http://[::1]:8080", "https://example.com", "http://[::2]"} {
+		dir := ResultDir(schema.ScanResult{Target: target, Timestamp: stamp}, out)
+		stamp = stamp.Add(time.Hour)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if target == "http://[::1]:8080" {
+			want = append(want, dir)
+		}
+	}
+	// A stray file with the prefix is not a result directory
+	if err := os.WriteFile(filepath.Join(out, "[__1]_8080_notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ResultDirs("http://[::1]:8080", out)
+	if err != nil {
+		t.Fatalf("ResultDirs: %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got, err := ResultDirs("https://example.com", filepath.Join(out, "missing")); err != nil || got != nil {
+		t.Errorf("missing output dir: got %v, %v, want nil, nil", got, err)
+	}
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yorozuya-cybersecurity/yorosec-agent/internal/schema"
+)
+
+var labelKey = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ParseLabels turns "key=value" pairs into a label map; env, when set, becomes the env label
+func ParseLabels(env string, pairs []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || v == "" || !labelKey.MatchString(k) {
+			return nil, fmt.Errorf("invalid label %q: expected key=value", p)
+		}
+		labels[k] = v
+	}
+	if env = strings.TrimSpace(env); env != "" {
+		labels[schema.EnvLabel] = env
+	}
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	return labels, nil
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Scope is an allowlist/denylist of domains ("example.com"), wildcard domains
+// ("*.example.com"), IP addresses and CIDR ranges ("10.0.0.0/8")
+type Scope struct {
+	Allow []string
+	Deny  []string
+}
+
+// Validate reports malformed scope entries
+func (s Scope) Validate() error {
+	for _, list := range [][]string{s.Allow, s.Deny} {
+		for _, p := range list {
+			if err := validatePattern(p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Check returns an error when target is denied or not covered by a non-empty allowlist.
+// Deny entries always win over allow entries.
+func (s Scope) Check(target string) error {
+	host := targetHost(target)
+	if host == "" {
+		return fmt.Errorf("cannot determine host for scope check of %q", target)
+	}
+	for _, p := range s.Deny {
+		if matchScope(p, host) {
+			return fmt.Errorf("target %s is explicitly denied by scope entry %q", target, p)
+		}
+	}
+	if len(s.Allow) == 0 {
+		return nil
+	}
+	for _, p := range s.Allow {
+		if matchScope(p, host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("target %s is out of scope (not matched by any scope.allow entry)", target)
+}
+
+// targetHost extracts the bare host from a normalized target
+func targetHost(target string) string {
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		return strings.ToLower(u.Hostname())
+	}
+	host := target
+	if h, _, err := net.SplitHostPort(target); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.Trim(host, "[]"))
+}
+
+func validatePattern(p string) error {
+	p = strings.TrimSpace(p)
+	switch {
+	case p == "":
+		return fmt.Errorf("empty scope entry")
+	case strings.Contains(p, "/"):
+		if _, _, err := net.ParseCIDR(p); err != nil {
+			return fmt.Errorf("invalid scope CIDR %q: %w", p, err)
+		}
+	case strings.HasPrefix(p, "*."):
+		if !validHost(strings.ToLower(p[2:])) {
+			return fmt.Errorf("invalid scope wildcard %q", p)
+		}
+	case strings.Contains(p, "*"):
+		return fmt.Errorf("invalid scope entry %q: only a leading \"*.\" wildcard is supported", p)
+	default:
+		if !validHost(strings.ToLower(p)) {
+			return fmt.Errorf("invalid scope entry %q", p)
+		}
+	}
+	return nil
+}
+
+func matchScope(pattern, host string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if strings.Contains(pattern, "/") {
+		_, cidr, err := net.ParseCIDR(pattern)
+		ip := net.ParseIP(host)
+		return err == nil && ip != nil && cidr.Contains(ip)
+	}
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+	if ip := net.ParseIP(pattern); ip != nil {
+		return ip.Equal(net.ParseIP(host))
+	}
+	return host == pattern
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// DefaultScheme is prepended to targets given without one
+var DefaultScheme = "https"
+
+// NormalizeTarget turns user input such as "example.com/", "HTTPS://Example.com:443"
+// or "http://example.com" into a canonical URL so scans and directory names are predictable.
+// CIDR ranges ("10.0.0.0/24") are returned in canonical prefix form; expand them with ExpandCIDR.
+func NormalizeTarget(raw string) (string, error) {
+	return NormalizeTargetScheme(raw, "")
+}
+
+// NormalizeTargetScheme is NormalizeTarget with the scheme used for targets that have
+// none. Precedence: a scheme in the target, then scheme, then DefaultScheme. Explicit
+// ports ("intranet:8080") are kept unless they are the scheme's default.
+func NormalizeTargetScheme(raw, scheme string) (string, error) {
+	scheme = strings.ToLower(strings.TrimSpace(scheme))
+	if scheme == "" {
+		scheme = DefaultScheme
+	}
+	if scheme != "http" && scheme != "https" {
+		return "", fmt.Errorf("invalid scheme %q (expected http or https)", scheme)
+	}
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return "", errors.New("target is empty")
+	}
+	if IsCIDR(s) {
+		p, _ := netip.ParsePrefix(s)
+		return p.Masked().String(), nil
+	}
+	if strings.ContainsAny(s, " \t\r\n") {
+		return "", fmt.Errorf("invalid target %q: contains whitespace", raw)
+	}
+	if !strings.Contains(s, "://") {
+		s = scheme + "://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid target %q: %w", raw, err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid target %q: unsupported scheme %q", raw, u.Scheme)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return "", fmt.Errorf("invalid target %q: missing host", raw)
+	}
+	if !validHost(host) {
+		return "", fmt.Errorf("invalid target %q: malformed host %q", raw, host)
+	}
+
+	// Drop ports that are implied by the scheme
+	port := u.Port()
+	if (u.Scheme == "https" && port == "443") || (u.Scheme == "http" && port == "80") {
+		port = ""
+	}
+	if port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	} else {
+		u.Host = host
+	}
+
+	u.User = nil
+	u.Fragment = ""
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// validHost accepts IP literals and DNS-style hostnames
+func validHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	if len(host) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 {
+			return false
+		}
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// stripScheme removes a leading "scheme://" so directory names stay short
+func stripScheme(target string) string {
+	if _, rest, ok := strings.Cut(target, "://"); ok {
+		return rest
+	}
+	return target
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeRange bounds scans by timestamp; a zero Since or Until leaves that end open
+type TimeRange struct {
+	Since time.Time
+	Until time.Time
+}
+
+// ParseTimeRange parses --since/--until values. Each is a date (2006-01-02), an RFC3339
+// time, or an age relative to now such as 90d, 2w or 36h. A date given as until
+// includes that whole day.
+func ParseTimeRange(since, until string, now time.Time) (TimeRange, error) {
+	var r TimeRange
+	var err error
+	if r.Since, err = parseTimeBound(since, now, false); err != nil {
+		return r, fmt.Errorf("invalid --since: %w", err)
+	}
+	if r.Until, err = parseTimeBound(until, now, true); err != nil {
+		return r, fmt.Errorf("invalid --until: %w", err)
+	}
+	if !r.Since.IsZero() && !r.Until.IsZero() && r.Until.Before(r.Since) {
+		return r, fmt.Errorf("--until %s is before --since %s", until, since)
+	}
+	return r, nil
+}
+
+// Contains reports whether t falls inside the range
+func (r TimeRange) Contains(t time.Time) bool {
+	if !r.Since.IsZero() && t.Before(r.Since) {
+		return false
+	}
+	if !r.Until.IsZero() && t.After(r.Until) {
+		return false
+	}
+	return true
+}
+
+func parseTimeBound(s string, now time.Time, endOfDay bool) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.UTC); err == nil {
+		if endOfDay {
+			t = t.Add(24*time.Hour - time.Nanosecond)
+		}
+		return t, nil
+	}
+	if d, err := parseAge(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date (2006-01-02), RFC3339 time or age such as 90d", s)
+}
+
+// parseAge accepts Go durations plus day (d) and week (w) units
+func parseAge(s string) (time.Duration, error) {
+	unit := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}[s[len(s)-1]]
+	if unit == 0 {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return d, nil
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return time.Duration(n) * unit, nil
+}